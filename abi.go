@@ -0,0 +1,153 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// importABIDir walks a directory of contract abi json files, and adds the
+// methods and events declared in them which are not already present in the
+// db. Supported are plain abi arrays, as emitted by solc, and compiler
+// artifacts carrying the abi in an "abi" field, as written by Hardhat,
+// Truffle or Foundry (in out/). Other json files, e.g. Hardhat debug files,
+// are skipped.
+func importABIDir(dir string, b *abidb.Builder) error {
+	var files, methods, events, known int
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		parsed, err := parseABIFile(data)
+		if err != nil {
+			log.Warn("Skipping bad abi file", "file", path, "err", err)
+			return nil
+		}
+		if parsed == nil {
+			return nil
+		}
+		files++
+		for _, m := range sortedMethods(parsed) {
+			if _, exists := b.Database().Methods.Get(fmt.Sprintf("%x", m.ID)); exists {
+				known++
+				continue
+			}
+			if err := b.AddMethod(m.ID, abidb.Entry{Signature: m.Sig, Source: path}); err != nil {
+				log.Warn("Rejected signature", "file", path, "err", err)
+				report.reject(rejectReason(err))
+				continue
+			}
+			methods++
+		}
+		for _, ev := range sortedEvents(parsed) {
+			if ev.Anonymous {
+				continue
+			}
+			if _, exists := b.Database().Events.Get(fmt.Sprintf("%x", ev.ID[:])); exists {
+				known++
+				continue
+			}
+			if err := b.AddEvent(ev.ID[:], abidb.Entry{Signature: markedEvent(ev), Source: path}); err != nil {
+				log.Warn("Rejected signature", "file", path, "err", err)
+				report.reject(rejectReason(err))
+				continue
+			}
+			events++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Info("Imported abi files", "dir", dir, "files", files, "methods", methods, "events", events, "known", known)
+	return nil
+}
+
+// parseABIFile parses a plain abi array, or the abi contained in a compiler
+// artifact. Json files without an abi yield nil.
+func parseABIFile(data []byte) (*abi.ABI, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var artifact struct {
+			ABI json.RawMessage `json:"abi"`
+		}
+		if err := json.Unmarshal(data, &artifact); err != nil {
+			return nil, err
+		}
+		data = bytes.TrimSpace(artifact.ABI)
+	}
+	if len(data) == 0 || data[0] != '[' {
+		return nil, nil
+	}
+	parsed, err := abi.JSON(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// sortedMethods returns the methods of the abi sorted by signature, so the
+// import output is deterministic.
+func sortedMethods(parsed *abi.ABI) []abi.Method {
+	methods := make([]abi.Method, 0, len(parsed.Methods))
+	for _, m := range parsed.Methods {
+		methods = append(methods, m)
+	}
+	sort.Slice(methods, func(i, j int) bool { return methods[i].Sig < methods[j].Sig })
+	return methods
+}
+
+// sortedEvents returns the events of the abi sorted by signature.
+func sortedEvents(parsed *abi.ABI) []abi.Event {
+	events := make([]abi.Event, 0, len(parsed.Events))
+	for _, ev := range parsed.Events {
+		events = append(events, ev)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Sig < events[j].Sig })
+	return events
+}
+
+// markedEvent returns the event signature in the stored form, with the indexed
+// parameters marked.
+func markedEvent(ev abi.Event) string {
+	params := make([]string, len(ev.Inputs))
+	for i, input := range ev.Inputs {
+		params[i] = input.Type.String()
+		if input.Indexed {
+			params[i] += " indexed"
+		}
+	}
+	return ev.RawName + "(" + strings.Join(params, ",") + ")"
+}
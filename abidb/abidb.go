@@ -0,0 +1,165 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package abidb validates method and event signatures, and collects them into
+// a database mapping 4-byte selectors (and 32-byte event topics) to the human
+// readable signatures, as consumed by clef.
+package abidb
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Entry is a single database record in the extended schema. The plain clef
+// format only contains the signature, the rest are optional annotations from
+// hand-maintained input lists.
+type Entry struct {
+	Signature  string   `json:"signature" yaml:"signature"`
+	Collisions []string `json:"collisions,omitempty" yaml:"collisions,omitempty"` // other valid signatures for the key
+	Trust      string   `json:"trust,omitempty" yaml:"trust,omitempty"`
+	Note       string   `json:"note,omitempty" yaml:"note,omitempty"`
+
+	// Provenance, only retained by the sqlite output
+	Source  string `json:"-" yaml:"-"` // file, or file and line, the signature was read from
+	AddedAt int64  `json:"-" yaml:"-"` // unix time the entry was first built, if known
+}
+
+// Signatures returns the signature followed by the colliding ones, if any.
+func (e *Entry) Signatures() []string {
+	return append([]string{e.Signature}, e.Collisions...)
+}
+
+// plain returns the value of the entry in the plain formats: the signature,
+// or a list of all signatures if there are collisions.
+func (e *Entry) plain() interface{} {
+	if len(e.Collisions) == 0 {
+		return e.Signature
+	}
+	return e.Signatures()
+}
+
+// Table maps hex-encoded keys to entries. Keys are always stored as lowercase
+// hex without 0x prefix, use KeyFormat to emit them differently.
+type Table struct {
+	keySize int
+	entries map[string]*Entry
+}
+
+// NewTable creates an empty table for keys of the given length, 4 for method
+// selectors or 32 for event topics.
+func NewTable(keySize int) *Table {
+	return &Table{keySize: keySize, entries: make(map[string]*Entry)}
+}
+
+// Get retrieves the entry for the given key.
+func (t *Table) Get(key string) (*Entry, bool) {
+	e, ok := t.entries[key]
+	return e, ok
+}
+
+// Lookup returns the signature of the given binary selector or topic, followed
+// by the colliding ones, if any. It matches the CompactTable lookup.
+func (t *Table) Lookup(key []byte) ([]string, bool) {
+	e, ok := t.entries[keyOf(key)]
+	if !ok {
+		return nil, false
+	}
+	return e.Signatures(), true
+}
+
+// Set inserts or replaces the entry for the given key.
+func (t *Table) Set(key string, e *Entry) {
+	t.entries[key] = e
+}
+
+// Len returns the number of entries in the table.
+func (t *Table) Len() int {
+	return len(t.entries)
+}
+
+// KeySize returns the length of the keys in the table, 4 for selectors or 32
+// for topics.
+func (t *Table) KeySize() int {
+	return t.keySize
+}
+
+// Keys returns all keys in the table, sorted. The table is not modified, so it
+// is safe to call concurrently with other readers.
+func (t *Table) Keys() []string {
+	keys := make([]string, 0, len(t.entries))
+	for key := range t.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Database is a set of validated signatures.
+type Database struct {
+	Methods *Table // 4-byte selectors to method signatures
+	Events  *Table // 32-byte topics to event signatures
+}
+
+// NewDatabase creates an empty database.
+func NewDatabase() *Database {
+	return &Database{Methods: NewTable(4), Events: NewTable(32)}
+}
+
+// Table returns the table the given selector or topic belongs in, or nil if
+// its length is invalid.
+func (db *Database) Table(key []byte) *Table {
+	switch len(key) {
+	case 4:
+		return db.Methods
+	case 32:
+		return db.Events
+	}
+	return nil
+}
+
+// ParseKey decodes a hex-encoded selector or topic. Both cases and an optional
+// 0x prefix are accepted, since downstream tools emit all variants.
+func ParseKey(key string) ([]byte, error) {
+	if len(key) >= 2 && key[0] == '0' && (key[1] == 'x' || key[1] == 'X') {
+		key = key[2:]
+	}
+	return hex.DecodeString(key)
+}
+
+// KeyFormat defines how keys are emitted by the encoders.
+type KeyFormat struct {
+	Prefix bool // whether to prepend 0x
+	Upper  bool // whether to emit uppercase hex digits
+}
+
+// Format converts a stored key into the configured format.
+func (f KeyFormat) Format(key string) string {
+	if f.Upper {
+		key = strings.ToUpper(key)
+	}
+	if f.Prefix {
+		key = "0x" + key
+	}
+	return key
+}
+
+// keyOf returns the stored form of a binary key.
+func keyOf(key []byte) string {
+	return fmt.Sprintf("%x", key)
+}
@@ -0,0 +1,235 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MismatchError is returned when a signature does not hash to the selector or
+// topic it was claimed for.
+type MismatchError struct {
+	Key       []byte // selector or topic claimed by the source
+	Signature string // signature found in the source
+	Computed  []byte // selector or topic computed from the signature
+}
+
+func (e *MismatchError) Error() string {
+	kind := "selector"
+	if len(e.Key) == 32 {
+		kind = "event"
+	}
+	return fmt.Sprintf("erroneous %s: %s, have %x want %x", kind, e.Signature, e.Key, e.Computed)
+}
+
+// Builder validates signatures, and collects the valid ones into a database.
+// Apart from Check, the builder is not safe for concurrent use.
+type Builder struct {
+	db         *Database
+	trusted    *Table // entries of an earlier build, not re-validated
+	mismatches []*MismatchError
+	rejected   int
+
+	lenient       bool // whether non-canonical signatures are rewritten
+	canonicalized int
+}
+
+// NewBuilder creates a builder with an empty database.
+func NewBuilder() *Builder {
+	return &Builder{db: NewDatabase()}
+}
+
+// Trust marks the methods of a previously built table as valid already, so
+// that adding them again with an identical signature skips the validation.
+// It must not be called concurrently with Check.
+func (b *Builder) Trust(t *Table) {
+	b.trusted = t
+}
+
+// Lenient makes the builder accept signatures using type aliases or stray
+// whitespace, which fail validation as claimed, if their canonical form hashes
+// to the claimed selector or topic. The canonical form is stored instead. It
+// must not be called concurrently with Check.
+func (b *Builder) Lenient() {
+	b.lenient = true
+}
+
+// Database returns the database built so far.
+func (b *Builder) Database() *Database {
+	return b.db
+}
+
+// Mismatches returns all rejected entries whose signature did not hash to
+// their claimed selector or topic.
+func (b *Builder) Mismatches() []*MismatchError {
+	return b.mismatches
+}
+
+// Rejected returns the number of entries which failed validation.
+func (b *Builder) Rejected() int {
+	return b.rejected
+}
+
+// Canonicalized returns the number of entries stored in their canonical form,
+// instead of the signature claimed.
+func (b *Builder) Canonicalized() int {
+	return b.canonicalized
+}
+
+// AddSelector validates a method signature, and adds it under its selector.
+func (b *Builder) AddSelector(sig string) error {
+	return b.AddMethod(crypto.Keccak256([]byte(sig))[:4], Entry{Signature: sig})
+}
+
+// AddMethod validates that the entry's signature hashes to the given selector
+// and is a valid abi method declaration, and adds or replaces the entry.
+func (b *Builder) AddMethod(selector []byte, e Entry) error {
+	if len(selector) != 4 {
+		b.rejected++
+		return fmt.Errorf("invalid selector, wrong length: %x", selector)
+	}
+	return b.Commit(b.Check(selector, e))
+}
+
+// AddEvent validates that the entry's event signature hashes to the given
+// topic and is a valid abi event declaration, and adds or replaces the entry.
+// Parameters may be marked as indexed, these markers are retained in the
+// stored (normalized) signature, but are not part of the hash.
+func (b *Builder) AddEvent(topic []byte, e Entry) error {
+	if len(topic) != 32 {
+		b.rejected++
+		return fmt.Errorf("invalid topic, wrong length: %x", topic)
+	}
+	return b.Commit(b.Check(topic, e))
+}
+
+// Checked is an entry validated against its claimed selector or topic, but
+// not added to the database yet.
+type Checked struct {
+	Key      []byte
+	Entry    Entry  // entry with its signature normalized
+	Err      error  // validation failure, if any
+	Original string // signature as claimed, if replaced by its canonical form
+}
+
+// Check validates an entry claimed for the given 4-byte selector or 32-byte
+// topic, without modifying the builder. It is safe to call concurrently, also
+// with Commit, so the expensive validation can be spread over several workers,
+// while committing the results in a deterministic order.
+func (b *Builder) Check(key []byte, e Entry) *Checked {
+	c := &Checked{Key: key, Entry: e}
+	switch len(key) {
+	case 4:
+		c.Err = b.verifyMethod(key, e.Signature)
+	case 32:
+		var marked string
+		if marked, c.Err = verifyEvent(key, e.Signature); c.Err == nil {
+			c.Entry.Signature = marked
+		}
+	default:
+		c.Err = fmt.Errorf("invalid sig, wrong length: %x", key)
+	}
+	if c.Err != nil && b.lenient {
+		b.canonicalize(c)
+	}
+	return c
+}
+
+// canonicalize retries the validation of a failed entry with its signature in
+// canonical form, replacing the signature if that passes.
+func (b *Builder) canonicalize(c *Checked) {
+	sig := c.Entry.Signature
+	switch len(c.Key) {
+	case 4:
+		canonical, err := Canonicalize(sig)
+		if err != nil || canonical == sig || b.verifyMethod(c.Key, canonical) != nil {
+			return
+		}
+		c.Entry.Signature = canonical
+	case 32:
+		canonical, err := canonicalEvent(sig)
+		if err != nil || canonical == sig {
+			return
+		}
+		marked, err := verifyEvent(c.Key, canonical)
+		if err != nil {
+			return
+		}
+		c.Entry.Signature = marked
+	default:
+		return
+	}
+	c.Original, c.Err = sig, nil
+}
+
+// Commit adds a checked entry to the database, replacing any existing one, or
+// records its rejection. The validation error is returned.
+func (b *Builder) Commit(c *Checked) error {
+	if c.Err != nil {
+		b.rejected++
+		if mismatch, ok := c.Err.(*MismatchError); ok {
+			b.mismatches = append(b.mismatches, mismatch)
+		}
+		return c.Err
+	}
+	if c.Original != "" {
+		b.canonicalized++
+	}
+	e := c.Entry
+	b.db.Table(c.Key).Set(keyOf(c.Key), &e)
+	return nil
+}
+
+func (b *Builder) verifyMethod(selector []byte, sig string) error {
+	if b.trusted != nil {
+		if known, ok := b.trusted.Get(keyOf(selector)); ok && known.Signature == sig {
+			return nil
+		}
+	}
+	// We do a basic sanity check here, not fully verifying the correctness of
+	// arguments, e.g the parameter types. We assume that the 4byte db comes
+	// from a somewhat trusted source. The hash is checked before the abi
+	// parsing, since the latter would reject mismatches too, but without
+	// telling us why.
+	want := crypto.Keccak256([]byte(sig))[:4]
+	if !bytes.Equal(selector, want) {
+		return &MismatchError{Key: selector, Signature: sig, Computed: want}
+	}
+	if err := testSelector(sig, selector); err != nil {
+		return fmt.Errorf("bad selector: %v, err: %v", sig, err)
+	}
+	return nil
+}
+
+func verifyEvent(topic []byte, sig string) (string, error) {
+	name, args, err := parseEvent(sig)
+	if err != nil {
+		return "", fmt.Errorf("bad event: %v, err: %v", sig, err)
+	}
+	canonical, marked := eventSignatures(name, args)
+	want := crypto.Keccak256([]byte(canonical))
+	if !bytes.Equal(topic, want) {
+		return "", &MismatchError{Key: topic, Signature: sig, Computed: want}
+	}
+	if err := testEvent(name, args, canonical, topic); err != nil {
+		return "", fmt.Errorf("bad event: %v, err: %v", sig, err)
+	}
+	return marked, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// CompactTable is a read-only form of a table for memory constrained lookups.
+// Signatures are mostly made of a few recurring parts, like `(`, `address` or
+// `uint256`, so they are split into tokens, which are interned into a single
+// token table. Each signature is then stored as the sequence of its token ids,
+// most of which fit a single byte. Lookups stay O(1), at the cost of decoding
+// the signature from its tokens.
+//
+// Where a Table takes around 180 bytes of memory per entry, for the hex key,
+// the entry and the map overhead, a compact one takes around 40, a fifth. Use
+// ReadCompact to load a database straight into compact form.
+type CompactTable struct {
+	keySize int
+	tokens  string   // all distinct tokens, concatenated
+	ends    []uint32 // end offset of each token in tokens
+	data    []byte   // per entry, uvarint token count followed by uvarint ids
+
+	selectors map[uint32]uint32 // selector to offset in data, for 4-byte keys
+	topics    map[string]uint32 // binary key to offset in data, otherwise
+}
+
+// tokenDelimiters are the characters which form tokens on their own, splitting
+// the names and types in between.
+const tokenDelimiters = "(),[]; "
+
+// tokenize splits a signature into its tokens, which concatenate back into the
+// signature.
+func tokenize(sig string) []string {
+	var tokens []string
+	for len(sig) > 0 {
+		n := strings.IndexAny(sig, tokenDelimiters)
+		switch {
+		case n < 0:
+			n = len(sig)
+		case n == 0:
+			n = 1
+		}
+		tokens = append(tokens, sig[:n])
+		sig = sig[n:]
+	}
+	return tokens
+}
+
+// CompactBuilder interns entries into a compact table one at a time, so that a
+// database can be loaded into compact form without ever holding a full Table.
+// Token ids are assigned in order of first appearance. The common tokens show
+// up in the first few signatures already, so they still get single byte ids.
+type CompactBuilder struct {
+	c      *CompactTable
+	ids    map[string]uint64
+	tokens strings.Builder
+	buf    [binary.MaxVarintLen64]byte
+}
+
+// NewCompactBuilder creates a builder for keys of the given length, 4 for
+// method selectors or 32 for event topics.
+func NewCompactBuilder(keySize int) *CompactBuilder {
+	c := &CompactTable{keySize: keySize}
+	if keySize == 4 {
+		c.selectors = make(map[uint32]uint32)
+	} else {
+		c.topics = make(map[string]uint32)
+	}
+	return &CompactBuilder{c: c, ids: make(map[string]uint64)}
+}
+
+// Add interns the signatures of a key, the first followed by the colliding
+// ones. Adding a key again replaces its signatures, the space used by the old
+// ones is not reclaimed though.
+func (b *CompactBuilder) Add(key []byte, sigs ...string) error {
+	if len(key) != b.c.keySize {
+		return fmt.Errorf("invalid key %x: want %d bytes", key, b.c.keySize)
+	}
+	if len(sigs) == 0 {
+		return fmt.Errorf("no signature for key %x", key)
+	}
+	c := b.c
+	if c.selectors != nil {
+		c.selectors[binary.BigEndian.Uint32(key)] = uint32(len(c.data))
+	} else {
+		c.topics[string(key)] = uint32(len(c.data))
+	}
+	tokens := tokenize(strings.Join(sigs, ";"))
+	c.data = append(c.data, b.buf[:binary.PutUvarint(b.buf[:], uint64(len(tokens)))]...)
+	for _, token := range tokens {
+		id, ok := b.ids[token]
+		if !ok {
+			id = uint64(len(c.ends))
+			b.ids[token] = id
+			b.tokens.WriteString(token)
+			c.ends = append(c.ends, uint32(b.tokens.Len()))
+		}
+		c.data = append(c.data, b.buf[:binary.PutUvarint(b.buf[:], id)]...)
+	}
+	return nil
+}
+
+// Finish returns the compact table of all entries added. The builder must not
+// be used afterwards.
+func (b *CompactBuilder) Finish() *CompactTable {
+	c := b.c
+	c.tokens = b.tokens.String()
+	b.c, b.ids = nil, nil
+	return c
+}
+
+// Compact interns the signatures of the table into a new compact table. The
+// table itself is not modified, and may be dropped afterwards.
+func (t *Table) Compact() *CompactTable {
+	b := NewCompactBuilder(t.KeySize())
+	for _, key := range t.Keys() {
+		bin, _ := ParseKey(key)
+		b.Add(bin, t.entries[key].Signatures()...)
+	}
+	return b.Finish()
+}
+
+// Lookup returns the signature of the given selector or topic, followed by
+// the colliding ones, if any.
+func (c *CompactTable) Lookup(key []byte) ([]string, bool) {
+	if len(key) != c.keySize {
+		return nil, false
+	}
+	var (
+		offset uint32
+		ok     bool
+	)
+	if c.selectors != nil {
+		offset, ok = c.selectors[binary.BigEndian.Uint32(key)]
+	} else {
+		offset, ok = c.topics[string(key)]
+	}
+	if !ok {
+		return nil, false
+	}
+	return strings.Split(c.decode(offset), ";"), true
+}
+
+// decode reassembles the token sequence at the given offset in the data.
+func (c *CompactTable) decode(offset uint32) string {
+	data := c.data[offset:]
+	count, n := binary.Uvarint(data)
+	data = data[n:]
+
+	var sig strings.Builder
+	for i := uint64(0); i < count; i++ {
+		id, n := binary.Uvarint(data)
+		data = data[n:]
+		start := uint32(0)
+		if id > 0 {
+			start = c.ends[id-1]
+		}
+		sig.WriteString(c.tokens[start:c.ends[id]])
+	}
+	return sig.String()
+}
+
+// Len returns the number of entries in the table.
+func (c *CompactTable) Len() int {
+	if c.selectors != nil {
+		return len(c.selectors)
+	}
+	return len(c.topics)
+}
+
+// KeySize returns the length of the keys in the table, 4 for selectors or 32
+// for topics.
+func (c *CompactTable) KeySize() int {
+	return c.keySize
+}
+
+// Tokens returns the number of distinct tokens interned.
+func (c *CompactTable) Tokens() int {
+	return len(c.ends)
+}
+
+// Size returns the number of bytes used by the token table and the encoded
+// signatures, excluding the key index.
+func (c *CompactTable) Size() int {
+	return len(c.tokens) + 4*len(c.ends) + len(c.data)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// compactTestTable returns a table with a collision and deeply nested types.
+func compactTestTable() *Table {
+	t := NewTable(4)
+	t.Set("06fdde03", &Entry{Signature: "name()"})
+	t.Set("70a08231", &Entry{Signature: "balanceOf(address)", Trust: "verified"})
+	t.Set("a9059cbb", &Entry{Signature: "transfer(address,uint256)", Collisions: []string{"many_msg_babbage(bytes1)"}})
+	t.Set("414bf389", &Entry{Signature: "exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))"})
+	t.Set("222ceb7c", &Entry{Signature: "foo(uint256[2][])"})
+	return t
+}
+
+// checkCompact checks that the compact table holds exactly the entries of the
+// table.
+func checkCompact(t *testing.T, tab *Table, c *CompactTable) {
+	t.Helper()
+	if c.Len() != tab.Len() || c.KeySize() != tab.KeySize() {
+		t.Fatalf("have %d entries of %d bytes, want %d of %d", c.Len(), c.KeySize(), tab.Len(), tab.KeySize())
+	}
+	for _, key := range tab.Keys() {
+		bin, _ := ParseKey(key)
+		e, _ := tab.Get(key)
+		if sigs, ok := c.Lookup(bin); !ok || !reflect.DeepEqual(sigs, e.Signatures()) {
+			t.Errorf("lookup %s: have %q (%v), want %q", key, sigs, ok, e.Signatures())
+		}
+	}
+	if sigs, ok := c.Lookup(make([]byte, tab.KeySize())); ok {
+		t.Errorf("unknown key found: %q", sigs)
+	}
+	if sigs, ok := c.Lookup(make([]byte, tab.KeySize()+1)); ok {
+		t.Errorf("wrong size key found: %q", sigs)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	tab := compactTestTable()
+	c := tab.Compact()
+	checkCompact(t, tab, c)
+
+	// The recurring tokens are interned only once
+	if have := c.Tokens(); have >= len(tokenize("exactInputSingle((address,address,uint24,address,uint256,uint256,uint256,uint160))")) {
+		t.Errorf("have %d tokens, recurring ones not interned", have)
+	}
+}
+
+func TestCompactBuilder(t *testing.T) {
+	b := NewCompactBuilder(32)
+	topic := bytes.Repeat([]byte{0xdd}, 32)
+	if err := b.Add(topic, "Approval(address,address,uint256)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(topic, "Transfer(address,address,uint256)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(topic[:4], "name()"); err == nil {
+		t.Error("selector accepted for topic table")
+	}
+	if err := b.Add(topic); err == nil {
+		t.Error("key without signature accepted")
+	}
+	c := b.Finish()
+	if c.Len() != 1 {
+		t.Fatalf("have %d entries, want 1", c.Len())
+	}
+	if sigs, _ := c.Lookup(topic); !reflect.DeepEqual(sigs, []string{"Transfer(address,address,uint256)"}) {
+		t.Errorf("have %q, want replaced signature", sigs)
+	}
+	if c := NewCompactBuilder(4).Finish(); c.Len() != 0 || c.KeySize() != 4 {
+		t.Errorf("empty table: have %d entries of %d bytes", c.Len(), c.KeySize())
+	}
+}
+
+func TestReadCompact(t *testing.T) {
+	tab := compactTestTable()
+	encoders := map[string]func(*bytes.Buffer) error{
+		"json":     func(buf *bytes.Buffer) error { _, err := tab.WriteJSON(buf, KeyFormat{}, false); return err },
+		"extended": func(buf *bytes.Buffer) error { _, err := tab.WriteJSON(buf, KeyFormat{Prefix: true}, true); return err },
+		"binary":   func(buf *bytes.Buffer) error { _, err := tab.WriteBinary(buf); return err },
+	}
+	for name, encode := range encoders {
+		var buf bytes.Buffer
+		if err := encode(&buf); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		c, err := ReadCompact(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		checkCompact(t, tab, c)
+	}
+}
+
+func TestReadCompactEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewTable(32).WriteBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	c, err := ReadCompact(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 0 || c.KeySize() != 32 {
+		t.Errorf("binary: have %d entries of %d bytes", c.Len(), c.KeySize())
+	}
+	if c, err = ReadCompact(strings.NewReader("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if c.Len() != 0 || c.KeySize() != 4 {
+		t.Errorf("json: have %d entries of %d bytes", c.Len(), c.KeySize())
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []string{
+		`["a9059cbb"]`,
+		`{"a9059cbb": "transfer(address,uint256)"`,
+		`{"a9059cbb": "transfer(address,uint256)"} {}`,
+		`{"a9059cbb": 1}`,
+		`{"xyz": "transfer(address,uint256)"}`,
+		`{"a9059cbb": "transfer(address,uint256)", "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef": "Transfer(address,address,uint256)"}`,
+	}
+	for _, in := range tests {
+		if _, err := ReadTable(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadTable accepted %s", in)
+		}
+		if _, err := ReadCompact(strings.NewReader(in)); err == nil {
+			t.Errorf("ReadCompact accepted %s", in)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/holiman/abidbbuilder/bindb"
+	"gopkg.in/yaml.v2"
+)
+
+// WriteTo writes the method selectors in the plain clef json format.
+func (db *Database) WriteTo(w io.Writer) (int64, error) {
+	return db.Methods.WriteJSON(w, KeyFormat{}, false)
+}
+
+// WriteJSON writes the table, sorted by key, as a json object. The plain format
+// maps keys to signatures, or lists of signatures for keys with collisions,
+// whereas the extended one maps them to entries. The object is streamed one
+// entry at a time, so apart from the sorted keys, no copy of the table is made
+// for encoding it. The output is identical to marshalling the whole object with
+// json.MarshalIndent.
+func (t *Table) WriteJSON(w io.Writer, keys KeyFormat, extended bool) (int64, error) {
+	indent := ""
+	if extended {
+		indent = " "
+	}
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	var value bytes.Buffer
+	for i, key := range t.Keys() {
+		if i == 0 {
+			cw.WriteString("{\n")
+		} else {
+			cw.WriteString(",\n")
+		}
+		name, err := json.Marshal(keys.Format(key))
+		if err != nil {
+			return cw.n, err
+		}
+		var val interface{} = t.entries[key].plain()
+		if extended {
+			val = t.entries[key]
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return cw.n, err
+		}
+		value.Reset()
+		if err := json.Indent(&value, raw, indent, indent); err != nil {
+			return cw.n, err
+		}
+		cw.WriteString(indent)
+		cw.Write(name)
+		cw.WriteString(": ")
+		cw.Write(value.Bytes())
+	}
+	if t.Len() == 0 {
+		cw.WriteString("{}")
+	} else {
+		cw.WriteString("\n}")
+	}
+	return cw.flush()
+}
+
+// WriteYAML writes the table, sorted by key, as a yaml mapping. One entry per
+// line keeps diffs of curated overlays easy to review. Like WriteJSON, entries
+// are streamed one at a time.
+func (t *Table) WriteYAML(w io.Writer, keys KeyFormat, extended bool) (int64, error) {
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, key := range t.Keys() {
+		e := t.entries[key]
+		name, err := yamlScalar(keys.Format(key))
+		if err != nil {
+			return cw.n, err
+		}
+		sigs := make([]string, 0, 1+len(e.Collisions))
+		for _, sig := range e.Signatures() {
+			v, err := yamlScalar(sig)
+			if err != nil {
+				return cw.n, err
+			}
+			sigs = append(sigs, v)
+		}
+		switch {
+		case extended:
+			fmt.Fprintf(cw, "%s:\n  signature: %s\n", name, sigs[0])
+			if len(sigs) > 1 {
+				cw.WriteString("  collisions:\n")
+				for _, sig := range sigs[1:] {
+					fmt.Fprintf(cw, "  - %s\n", sig)
+				}
+			}
+			for _, field := range []struct{ name, value string }{{"trust", e.Trust}, {"note", e.Note}} {
+				if field.value == "" {
+					continue
+				}
+				v, err := yamlScalar(field.value)
+				if err != nil {
+					return cw.n, err
+				}
+				fmt.Fprintf(cw, "  %s: %s\n", field.name, v)
+			}
+		case len(sigs) == 1:
+			fmt.Fprintf(cw, "%s: %s\n", name, sigs[0])
+		default:
+			fmt.Fprintf(cw, "%s:\n", name)
+			for _, sig := range sigs {
+				fmt.Fprintf(cw, "- %s\n", sig)
+			}
+		}
+	}
+	if t.Len() == 0 {
+		cw.WriteString("{}\n")
+	}
+	return cw.flush()
+}
+
+// yamlScalar encodes a string as a single line yaml scalar. The yaml package
+// folds long strings at spaces, which would split long event signatures over
+// several lines, so those are written as double-quoted json strings instead,
+// which yaml reads back the same.
+func yamlScalar(s string) (string, error) {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	if line := strings.TrimSuffix(string(data), "\n"); !strings.Contains(line, "\n") {
+		return line, nil
+	}
+	quoted, err := json.Marshal(s)
+	return string(quoted), err
+}
+
+// goChunk is the number of entries filled in by a single init function of the
+// generated Go source. Huge functions or composite literals make the compiler
+// slow and hungry, or hit its limits outright.
+const goChunk = 10000
+
+// WriteGo writes the table as a Go source file of the given package, which
+// declares the named map from keys to signatures, colliding ones ;-separated.
+// The map is filled by several init functions, each covering a chunk of the
+// entries, so arbitrarily large tables compile. The package and map names
+// must be valid Go identifiers.
+func (t *Table) WriteGo(w io.Writer, pkg, name string, keys KeyFormat) (int64, error) {
+	if !token.IsIdentifier(pkg) {
+		return 0, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if !token.IsIdentifier(name) {
+		return 0, fmt.Errorf("invalid variable name %q", name)
+	}
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	kind := "method selectors to method"
+	if t.KeySize() == 32 {
+		kind = "event topics to event"
+	}
+	fmt.Fprintf(cw, "// Code generated by abidbbuilder. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	fmt.Fprintf(cw, "// %s maps %s signatures.\n", name, kind)
+	fmt.Fprintf(cw, "var %s = make(map[string]string, %d)\n", name, t.Len())
+	for i, key := range t.Keys() {
+		if i%goChunk == 0 {
+			if i > 0 {
+				cw.WriteString("}\n")
+			}
+			cw.WriteString("\nfunc init() {\n")
+		}
+		fmt.Fprintf(cw, "\t%s[%s] = %s\n", name, strconv.Quote(keys.Format(key)),
+			strconv.Quote(strings.Join(t.entries[key].Signatures(), ";")))
+	}
+	if t.Len() > 0 {
+		cw.WriteString("}\n")
+	}
+	return cw.flush()
+}
+
+// countingWriter is a buffered writer counting the bytes written, which keeps
+// the first error, so the encoders need to check only once at the end.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+func (cw *countingWriter) WriteString(s string) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+	n, err := cw.w.WriteString(s)
+	cw.n += int64(n)
+	cw.err = err
+	return n, err
+}
+
+// flush writes out the buffered data, returning the total count and the first
+// error encountered.
+func (cw *countingWriter) flush() (int64, error) {
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+	return cw.n, cw.err
+}
+
+// WriteBinary writes the signatures of the table in the compact binary format
+// of the bindb package, colliding signatures ;-separated.
+func (t *Table) WriteBinary(w io.Writer) (int64, error) {
+	var (
+		keys       = make([][]byte, 0, t.Len())
+		signatures = make([]string, 0, t.Len())
+	)
+	// Keys are normalized lowercase hex, so their order is the binary order
+	for _, key := range t.Keys() {
+		bin, err := ParseKey(key)
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, bin)
+		signatures = append(signatures, strings.Join(t.entries[key].Signatures(), ";"))
+	}
+	return bindb.Encode(w, t.KeySize(), keys, signatures)
+}
+
+// ReadTable reads a table written by WriteJSON, in either the plain or the
+// extended format, or by WriteBinary. Keys are normalized, so any KeyFormat is
+// accepted.
+func ReadTable(r io.Reader) (*Table, error) {
+	t := NewTable(4)
+	keySize, err := readEntries(r, func(key []byte, e *Entry) error {
+		if t.Len() == 0 {
+			t.keySize = len(key)
+		} else if len(key) != t.keySize {
+			return fmt.Errorf("invalid key %x: mixed key sizes %d and %d", key, t.keySize, len(key))
+		}
+		t.Set(keyOf(key), e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if keySize != 0 {
+		t.keySize = keySize
+	}
+	return t, nil
+}
+
+// ReadCompact reads a table like ReadTable, but interns the entries into a
+// compact table as they are read, without holding them all in between.
+func ReadCompact(r io.Reader) (*CompactTable, error) {
+	var b *CompactBuilder
+	keySize, err := readEntries(r, func(key []byte, e *Entry) error {
+		if b == nil {
+			b = NewCompactBuilder(len(key))
+		}
+		return b.Add(key, e.Signatures()...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		// Json carries no key size, selectors if empty
+		if keySize == 0 {
+			keySize = 4
+		}
+		b = NewCompactBuilder(keySize)
+	}
+	return b.Finish(), nil
+}
+
+// readEntries decodes the json or binary table, passing the entries one by one
+// to fn. It returns the key size if the format carries one, zero otherwise.
+func readEntries(r io.Reader, fn func(key []byte, e *Entry) error) (int, error) {
+	br := bufio.NewReader(r)
+	if head, _ := br.Peek(len(bindb.Magic)); string(head) == bindb.Magic {
+		data, err := ioutil.ReadAll(br)
+		if err != nil {
+			return 0, err
+		}
+		return readBinary(data, fn)
+	}
+	dec := json.NewDecoder(br)
+	if tok, err := dec.Token(); err != nil {
+		return 0, err
+	} else if tok != json.Delim('{') {
+		return 0, fmt.Errorf("invalid table: want json object, have %v", tok)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, err
+		}
+		key := tok.(string) // object keys are always strings
+		var val json.RawMessage
+		if err := dec.Decode(&val); err != nil {
+			return 0, err
+		}
+		bin, err := ParseKey(key)
+		if err != nil {
+			return 0, fmt.Errorf("invalid key %q: %v", key, err)
+		}
+		e := new(Entry)
+		if err := json.Unmarshal(val, &e.Signature); err != nil {
+			var sigs []string
+			if err := json.Unmarshal(val, &sigs); err == nil && len(sigs) > 0 {
+				e.Signature, e.Collisions = sigs[0], sigs[1:]
+			} else if err := json.Unmarshal(val, e); err != nil {
+				return 0, fmt.Errorf("invalid entry %q: %v", key, err)
+			}
+		}
+		if err := fn(bin, e); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return 0, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return 0, fmt.Errorf("invalid table: trailing data after json object")
+	}
+	return 0, nil
+}
+
+// readBinary decodes a table written by WriteBinary, passing the entries one
+// by one to fn. It returns the key size of the table.
+func readBinary(data []byte, fn func(key []byte, e *Entry) error) (int, error) {
+	db, err := bindb.Open(data)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < db.Len(); i++ {
+		sigs := strings.Split(db.Signature(i), ";")
+		if err := fn(db.Key(i), &Entry{Signature: sigs[0], Collisions: sigs[1:]}); err != nil {
+			return 0, err
+		}
+	}
+	return db.KeySize(), nil
+}
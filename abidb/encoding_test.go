@@ -0,0 +1,187 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Tests that empty tables keep their key size, instead of being taken for
+// selector tables.
+func TestEmptyTableKeySize(t *testing.T) {
+	db := NewDatabase()
+	if have := db.Events.KeySize(); have != 32 {
+		t.Fatalf("event table key size: have %d, want 32", have)
+	}
+	var buf bytes.Buffer
+	if _, err := db.Events.WriteBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadTable(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := read.KeySize(); have != 32 {
+		t.Errorf("binary round trip key size: have %d, want 32", have)
+	}
+	buf.Reset()
+	if _, err := db.Events.WriteGo(&buf, "fourbyte", "embeddedEvents", KeyFormat{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "maps event topics") {
+		t.Errorf("empty event table written as methods:\n%s", buf.String())
+	}
+}
+
+// Tests that names which would not compile are rejected.
+func TestWriteGoNames(t *testing.T) {
+	tab := NewTable(4)
+	tab.Set("a9059cbb", &Entry{Signature: "transfer(address,uint256)"})
+	tests := []struct {
+		pkg, name string
+		ok        bool
+	}{
+		{"fourbyte", "embedded", true},
+		{"four_byte", "Embedded2", true},
+		{"four-byte", "embedded", false},
+		{"fourbyte", "embedded = nil; var x", false},
+		{"func", "embedded", false},
+		{"fourbyte", "", false},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		_, err := tab.WriteGo(&buf, tt.pkg, tt.name, KeyFormat{})
+		if tt.ok && err != nil {
+			t.Errorf("%q %q: unexpected error: %v", tt.pkg, tt.name, err)
+		}
+		if !tt.ok && (err == nil || buf.Len() > 0) {
+			t.Errorf("%q %q: accepted", tt.pkg, tt.name)
+		}
+	}
+}
+
+func TestReadTableMixedKeys(t *testing.T) {
+	in := `{"a9059cbb": "transfer(address,uint256)", "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef": "Transfer(address,address,uint256)"}`
+	if _, err := ReadTable(strings.NewReader(in)); err == nil {
+		t.Fatal("mixed key sizes accepted")
+	}
+}
+
+// Tests that long signatures are not wrapped, keeping one entry per line.
+func TestWriteYAMLLongSignatures(t *testing.T) {
+	tab := NewTable(32)
+	tab.Set("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", &Entry{Signature: "Transfer(address indexed,address indexed,uint256)"})
+	tab.Set("597921fa00000000000000000000000000000000000000000000000000000000", &Entry{Signature: "fulfillOrder(((address,address,(uint8,address,uint256,uint256,uint256)[],(uint8,address,uint256,uint256,uint256,address)[],uint8,uint256,uint256,bytes32,uint256,bytes32,uint256),uint120,uint120,bytes,bytes),bytes32)"})
+
+	var buf bytes.Buffer
+	if _, err := tab.WriteYAML(&buf, KeyFormat{}, false); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != tab.Len() {
+		t.Fatalf("have %d lines, want %d:\n%s", lines, tab.Len(), buf.String())
+	}
+	var decoded map[string]string
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range tab.Keys() {
+		e, _ := tab.Get(key)
+		if decoded[key] != e.Signature {
+			t.Errorf("%s: have %q, want %q", key, decoded[key], e.Signature)
+		}
+	}
+}
+
+// Tests that the extended yaml output decodes into the entries written.
+func TestWriteYAMLExtended(t *testing.T) {
+	tab := NewTable(32)
+	tab.Set("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", &Entry{
+		Signature:  "Transfer(address indexed,address indexed,uint256)",
+		Collisions: []string{"Transfer(address indexed,address indexed,uint256 indexed) with a name long enough to be folded"},
+		Trust:      "verified",
+		Note:       "quirky: has # and \"quotes\"",
+	})
+	tab.Set("1111111111111111111111111111111111111111111111111111111111111111", &Entry{Signature: "true"})
+
+	var buf bytes.Buffer
+	if _, err := tab.WriteYAML(&buf, KeyFormat{Prefix: true}, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]*Entry
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != tab.Len() {
+		t.Fatalf("have %d entries, want %d:\n%s", len(decoded), tab.Len(), buf.String())
+	}
+	for _, key := range tab.Keys() {
+		e, _ := tab.Get(key)
+		if have := decoded["0x"+key]; !reflect.DeepEqual(have, e) {
+			t.Errorf("%s: have %+v, want %+v", key, have, e)
+		}
+	}
+}
+
+// Tests that the streamed json output is identical to marshalling the whole
+// object at once.
+func TestWriteJSONMarshalIndent(t *testing.T) {
+	full := NewTable(4)
+	full.Set("a9059cbb", &Entry{Signature: "transfer(address,uint256)", Collisions: []string{"many_msg_babbage(bytes1)"}, Trust: "verified"})
+	full.Set("70a08231", &Entry{Signature: "balanceOf(address)", Note: "<html> & \"quotes\""})
+	full.Set("06fdde03", &Entry{Signature: "name()"})
+
+	for _, tab := range []*Table{full, NewTable(4)} {
+		for _, extended := range []bool{false, true} {
+			for _, keys := range []KeyFormat{{}, {Prefix: true, Upper: true}} {
+				obj := make(map[string]interface{})
+				for _, key := range tab.Keys() {
+					e, _ := tab.Get(key)
+					if extended {
+						obj[keys.Format(key)] = e
+					} else {
+						obj[keys.Format(key)] = e.plain()
+					}
+				}
+				indent := ""
+				if extended {
+					indent = " "
+				}
+				want, err := json.MarshalIndent(obj, "", indent)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var have bytes.Buffer
+				n, err := tab.WriteJSON(&have, keys, extended)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if have.String() != string(want) {
+					t.Errorf("%d entries, extended %v, keys %+v: have\n%s\nwant\n%s", tab.Len(), extended, keys, have.String(), want)
+				}
+				if n != int64(have.Len()) {
+					t.Errorf("reported %d bytes written, have %d", n, have.Len())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// abiArg is a parameter in the ABI JSON format. Tuples are expanded into their
+// components, which the abi package requires to be named, so they are given
+// positional names. These don't end up in the canonical signature.
+type abiArg struct {
+	Name       string   `json:"name,omitempty"`
+	Type       string   `json:"type"`
+	Components []abiArg `json:"components,omitempty"`
+	Indexed    bool     `json:"indexed,omitempty"`
+}
+
+// canonical returns the type as it appears in a canonical signature.
+func (arg abiArg) canonical() string {
+	if !strings.HasPrefix(arg.Type, "tuple") {
+		return arg.Type
+	}
+	types := make([]string, len(arg.Components))
+	for i, c := range arg.Components {
+		types[i] = c.canonical()
+	}
+	return "(" + strings.Join(types, ",") + ")" + strings.TrimPrefix(arg.Type, "tuple")
+}
+
+// sigParser is a recursive descent parser for method and event signatures.
+//
+//	signature := name '(' params ')'
+//	params    := [ param { ',' param } ]
+//	param     := ( elementary | '(' params ')' ) { '[' [ digits ] ']' } [ 'indexed' ]
+//
+// The indexed marker is only accepted for events, where whitespace is also
+// allowed between tokens.
+type sigParser struct {
+	input   string
+	pos     int
+	indexed bool // whether parameters may be marked as indexed (events)
+}
+
+// parseSignature splits a method or event signature into its name and its
+// parameters, with tuples expanded into components.
+func parseSignature(signature string, indexed bool) (string, []abiArg, error) {
+	open := strings.IndexByte(signature, '(')
+	if open <= 0 {
+		return "", nil, fmt.Errorf("missing name or parameter list")
+	}
+	name := signature[:open]
+	if strings.ContainsAny(name, "), \t") {
+		return "", nil, fmt.Errorf("invalid name %q", name)
+	}
+	p := &sigParser{input: signature, pos: open, indexed: indexed}
+	args, err := p.parseParams(true)
+	if err != nil {
+		return "", nil, err
+	}
+	if p.pos != len(p.input) {
+		return "", nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos:], p.pos)
+	}
+	return name, args, nil
+}
+
+// parseParams parses a parenthesized parameter list. Top-level parameters are
+// the only ones which may be marked as indexed.
+func (p *sigParser) parseParams(top bool) ([]abiArg, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	args := make([]abiArg, 0)
+	if p.skipSpace(); p.peek() == ')' {
+		p.pos++
+		return args, nil
+	}
+	for {
+		arg, err := p.parseParam(top)
+		if err != nil {
+			return nil, err
+		}
+		if !top {
+			arg.Name = fmt.Sprintf("f%d", len(args))
+		}
+		args = append(args, arg)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ')':
+			p.pos++
+			return args, nil
+		default:
+			return nil, p.unexpected()
+		}
+	}
+}
+
+// parseParam parses a single, possibly tuple, parameter type.
+func (p *sigParser) parseParam(top bool) (abiArg, error) {
+	var arg abiArg
+	if p.skipSpace(); p.peek() == '(' {
+		components, err := p.parseParams(false)
+		if err != nil {
+			return arg, err
+		}
+		arg.Type, arg.Components = "tuple", components
+	} else {
+		start := p.pos
+		for isAlphaNum(p.peek()) {
+			p.pos++
+		}
+		if p.pos == start {
+			return arg, p.unexpected()
+		}
+		arg.Type = p.input[start:p.pos]
+	}
+	// Consume any array suffixes
+	for p.peek() == '[' {
+		start := p.pos
+		for p.pos++; p.peek() >= '0' && p.peek() <= '9'; p.pos++ {
+		}
+		if err := p.expect(']'); err != nil {
+			return arg, err
+		}
+		arg.Type += p.input[start:p.pos]
+	}
+	// Consume the indexed marker, if allowed
+	if p.indexed && top {
+		save := p.pos
+		p.skipSpace()
+		if p.pos > save && strings.HasPrefix(p.input[p.pos:], "indexed") {
+			p.pos += len("indexed")
+			arg.Indexed = true
+		} else {
+			p.pos = save
+		}
+	}
+	return arg, nil
+}
+
+// skipSpace skips whitespace, if allowed in the signature.
+func (p *sigParser) skipSpace() {
+	for p.indexed && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+// peek returns the next character, or 0 at the end of the input.
+func (p *sigParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// expect consumes the given character.
+func (p *sigParser) expect(c byte) error {
+	if p.peek() != c {
+		return fmt.Errorf("expected %q at offset %d", c, p.pos)
+	}
+	p.pos++
+	return nil
+}
+
+// unexpected returns an error for the character at the current position.
+func (p *sigParser) unexpected() error {
+	if p.pos >= len(p.input) {
+		return fmt.Errorf("unexpected end of signature")
+	}
+	return fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+}
+
+func isAlphaNum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// parseSelector converts a method selector into an ABI JSON spec. The returned
+// data is a valid JSON string which can be consumed by the standard abi package.
+//
+// Note, although uppercase letters are not part of the ABI spec, the parser
+// still accepts them as the general format is valid. They will be rejected
+// later by the type checker.
+func parseSelector(selector string) ([]byte, error) {
+	// Define a tiny fake ABI struct for JSON marshalling
+	type fakeABI struct {
+		Name   string   `json:"name"`
+		Type   string   `json:"type"`
+		Inputs []abiArg `json:"inputs"`
+	}
+	// Validate the selector and extract it's components
+	name, arguments, err := parseSignature(selector, false)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal([]fakeABI{{name, "function", arguments}})
+}
+
+// testSelector checks that the selector is a valid abi method declaration with
+// the given id.
+func testSelector(selector string, id []byte) error {
+	m, err := Method(selector)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(m.ID, id) {
+		return fmt.Errorf("no method with id: %#x", id)
+	}
+	if m.Sig != selector {
+		return fmt.Errorf("Expected equality: %v != %v", m.Sig, selector)
+	}
+	return nil
+}
+
+// Method converts a method signature into an abi method declaration, which
+// can be used to decode calldata. The parameters are unnamed.
+func Method(signature string) (*abi.Method, error) {
+	abistring, err := parseSelector(signature)
+	if err != nil {
+		return nil, err
+	}
+	abistruct, err := abi.JSON(bytes.NewReader(abistring))
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range abistruct.Methods {
+		return &m, nil
+	}
+	return nil, fmt.Errorf("no method in %v", signature)
+}
+
+// parseEvent splits an event signature into its name and parameters. Contrary
+// to method selectors, event parameters may carry an indexed marker, e.g.
+// `Transfer(address indexed,uint256)`.
+func parseEvent(signature string) (string, []abiArg, error) {
+	return parseSignature(signature, true)
+}
+
+// eventSignatures returns the canonical signature of an event, which is hashed
+// into its topic, and the normalized one including the indexed markers.
+func eventSignatures(name string, args []abiArg) (string, string) {
+	canonical := make([]string, len(args))
+	marked := make([]string, len(args))
+	for i, arg := range args {
+		canonical[i] = arg.canonical()
+		marked[i] = canonical[i]
+		if arg.Indexed {
+			marked[i] += " indexed"
+		}
+	}
+	return name + "(" + strings.Join(canonical, ",") + ")", name + "(" + strings.Join(marked, ",") + ")"
+}
+
+// typeAliases maps the elementary type aliases of Solidity to their canonical
+// types, which are the ones hashed into selectors and topics.
+var typeAliases = map[string]string{
+	"uint":   "uint256",
+	"int":    "int256",
+	"byte":   "bytes1",
+	"fixed":  "fixed128x18",
+	"ufixed": "ufixed128x18",
+}
+
+// canonicalArgs replaces the type aliases of the parameters, including tuple
+// components, with their canonical types.
+func canonicalArgs(args []abiArg) {
+	for i := range args {
+		base, suffix := args[i].Type, ""
+		if n := strings.IndexByte(base, '['); n >= 0 {
+			base, suffix = base[:n], base[n:]
+		}
+		if canonical, ok := typeAliases[base]; ok {
+			args[i].Type = canonical + suffix
+		}
+		canonicalArgs(args[i].Components)
+	}
+}
+
+// Canonicalize rewrites a method signature into its canonical form, dropping
+// all whitespace and replacing type aliases, e.g. `transfer(address, uint)`
+// becomes `transfer(address,uint256)`. The result is not validated otherwise.
+func Canonicalize(signature string) (string, error) {
+	name, args, err := parseSignature(strings.Join(strings.Fields(signature), ""), false)
+	if err != nil {
+		return "", err
+	}
+	canonicalArgs(args)
+	canonical, _ := eventSignatures(name, args)
+	return canonical, nil
+}
+
+// canonicalEvent rewrites an event signature into its normalized form with the
+// type aliases replaced, retaining the indexed markers.
+func canonicalEvent(signature string) (string, error) {
+	name, args, err := parseEvent(strings.TrimSpace(signature))
+	if err != nil {
+		return "", err
+	}
+	canonicalArgs(args)
+	_, marked := eventSignatures(name, args)
+	return marked, nil
+}
+
+// testEvent checks that the event is a valid abi event declaration with the
+// given topic.
+func testEvent(name string, args []abiArg, canonical string, topic []byte) error {
+	type fakeABI struct {
+		Name      string   `json:"name"`
+		Type      string   `json:"type"`
+		Anonymous bool     `json:"anonymous"`
+		Inputs    []abiArg `json:"inputs"`
+	}
+	abistring, err := json.Marshal([]fakeABI{{name, "event", false, args}})
+	if err != nil {
+		return err
+	}
+	abistruct, err := abi.JSON(bytes.NewReader(abistring))
+	if err != nil {
+		return err
+	}
+	ev, err := abistruct.EventByID(common.BytesToHash(topic))
+	if err != nil {
+		return err
+	}
+	if ev.Sig != canonical {
+		return fmt.Errorf("Expected equality: %v != %v", ev.Sig, canonical)
+	}
+	return nil
+}
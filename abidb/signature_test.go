@@ -0,0 +1,179 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package abidb
+
+import (
+	"testing"
+)
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		sig    string
+		events bool   // whether to parse as event, allowing indexed markers
+		want   string // normalized signature, empty if invalid
+	}{
+		{"f()", false, "f()"},
+		{"transfer(address,uint256)", false, "transfer(address,uint256)"},
+		{"f(uint256[2][],bytes)", false, "f(uint256[2][],bytes)"},
+		// Nested tuples and tuple arrays
+		{"f((uint256,bool))", false, "f((uint256,bool))"},
+		{"f((uint256,(address,bytes32)[]),bool)", false, "f((uint256,(address,bytes32)[]),bool)"},
+		{"f((uint256,bool)[2][])", false, "f((uint256,bool)[2][])"},
+		{"f(())", false, "f(())"},
+		// Malformed
+		{"f(,)", false, ""},
+		{"f(uint256,)", false, ""},
+		{"f(", false, ""},
+		{"f(uint256", false, ""},
+		{"f((uint256)", false, ""},
+		{"f(uint256[)", false, ""},
+		{"f(uint256)x", false, ""},
+		{"f(uint256))", false, ""},
+		{"(uint256)", false, ""},
+		{"f", false, ""},
+		{"f g(uint256)", false, ""},
+		{"", false, ""},
+		// Whitespace and indexed markers are only allowed in events
+		{"f(uint256 indexed)", false, ""},
+		{"f(address, uint256)", false, ""},
+		{"Transfer(address indexed,address indexed,uint256)", true, "Transfer(address indexed,address indexed,uint256)"},
+		{"Transfer(address indexed, address indexed, uint256)", true, "Transfer(address indexed,address indexed,uint256)"},
+		{"E((uint256,bool) indexed)", true, "E((uint256,bool) indexed)"},
+		{"E(uint256[] indexed,bytes)", true, "E(uint256[] indexed,bytes)"},
+		{"E((uint256 indexed,bool))", true, ""},
+		{"E(uint256 indexed indexed)", true, ""},
+		// Types are only checked by the abi package, not the parser
+		{"E(uint256indexed)", true, "E(uint256indexed)"},
+		{"f(uint)", false, "f(uint)"},
+	}
+	for _, tt := range tests {
+		name, args, err := parseSignature(tt.sig, tt.events)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q: accepted, want error", tt.sig)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.sig, err)
+			continue
+		}
+		if _, have := eventSignatures(name, args); have != tt.want {
+			t.Errorf("%q: have %q, want %q", tt.sig, have, tt.want)
+		}
+	}
+}
+
+func TestEventSignatures(t *testing.T) {
+	name, args, err := parseEvent("Transfer(address indexed,address indexed,uint256)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, marked := eventSignatures(name, args)
+	if canonical != "Transfer(address,address,uint256)" {
+		t.Errorf("canonical: have %q", canonical)
+	}
+	if marked != "Transfer(address indexed,address indexed,uint256)" {
+		t.Errorf("marked: have %q", marked)
+	}
+}
+
+func TestMethod(t *testing.T) {
+	m, err := Method("f((uint256,bool)[2],bytes)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Sig != "f((uint256,bool)[2],bytes)" {
+		t.Errorf("have %q", m.Sig)
+	}
+	if _, err := Method("f(uint)"); err == nil {
+		t.Error("alias accepted by the abi package")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		sig  string
+		want string // empty if invalid
+	}{
+		{"transfer(address,uint256)", "transfer(address,uint256)"},
+		{"transfer(address,uint)", "transfer(address,uint256)"},
+		{"transfer(address, uint)", "transfer(address,uint256)"},
+		{" transfer (address , uint) ", "transfer(address,uint256)"},
+		{"f(uint[])", "f(uint256[])"},
+		{"f(int[2][])", "f(int256[2][])"},
+		{"f((uint,int)[2])", "f((uint256,int256)[2])"},
+		{"f((uint,(byte,int)[])[],bytes)", "f((uint256,(bytes1,int256)[])[],bytes)"},
+		{"f(byte,bytes)", "f(bytes1,bytes)"},
+		{"f(fixed,ufixed[3])", "f(fixed128x18,ufixed128x18[3])"},
+		// Sized types and lookalikes are not aliases
+		{"f(uint8,int128,bytes32)", "f(uint8,int128,bytes32)"},
+		{"f(uints,byte1)", "f(uints,byte1)"},
+		{"f()", "f()"},
+		{"f(uint", ""},
+		{"f(,)", ""},
+	}
+	for _, tt := range tests {
+		have, err := Canonicalize(tt.sig)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q: accepted as %q, want error", tt.sig, have)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tt.sig, err)
+		} else if have != tt.want {
+			t.Errorf("%q: have %q, want %q", tt.sig, have, tt.want)
+		}
+	}
+}
+
+func TestCanonicalEvent(t *testing.T) {
+	have, err := canonicalEvent("Transfer(address indexed, address indexed, uint)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Transfer(address indexed,address indexed,uint256)"; have != want {
+		t.Errorf("have %q, want %q", have, want)
+	}
+}
+
+func TestLenientBuilder(t *testing.T) {
+	selector := []byte{0xa9, 0x05, 0x9c, 0xbb} // transfer(address,uint256)
+
+	strict := NewBuilder()
+	if c := strict.Check(selector, Entry{Signature: "transfer(address,uint)"}); c.Err == nil {
+		t.Fatal("strict builder accepted alias")
+	}
+	lenient := NewBuilder()
+	lenient.Lenient()
+	c := lenient.Check(selector, Entry{Signature: "transfer(address,uint)"})
+	if c.Err != nil {
+		t.Fatalf("lenient builder rejected alias: %v", c.Err)
+	}
+	if c.Entry.Signature != "transfer(address,uint256)" || c.Original != "transfer(address,uint)" {
+		t.Errorf("have %q from %q", c.Entry.Signature, c.Original)
+	}
+	// Canonical forms still need to match the selector
+	if c := lenient.Check(selector, Entry{Signature: "approve(address,uint)"}); c.Err == nil {
+		t.Error("lenient builder accepted mismatch")
+	}
+	if err := lenient.Commit(c); err != nil || lenient.Canonicalized() != 1 {
+		t.Errorf("canonicalized count: have %d, err %v", lenient.Canonicalized(), err)
+	}
+}
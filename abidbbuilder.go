@@ -17,189 +17,446 @@
 package main
 
 import (
-	"bytes"
-	"encoding/hex"
-	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
-	"log"
+	"go/token"
+	"net/http"
 	"os"
-	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/iancoleman/orderedmap"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
 )
 
 var (
-	inDir   = flag.String("i", "", "input directory to read")
-	outFile = flag.String("o", "", "file to write to (overwrites if exists)")
+	inDir      = flag.String("i", "", "input directory (or packed file) to read")
+	outFile    = flag.String("o", "", "file to write to (overwrites if exists)")
+	eventsFile = flag.String("events", "", "file to write event signatures to, in the same format(s) as -o (optional)")
+	formats    = flag.String("format", "json", "comma-separated output formats, each written next to -o with its own extension if several")
+	keyPrefix  = flag.Bool("key-prefix", false, "emit selector keys with a 0x prefix")
+	keyCase    = flag.String("key-case", "lower", "case of emitted selector keys (lower or upper)")
+	goPkgFlag  = flag.String("go-package", "fourbyte", "package name of the gosrc output")
+	goVarFlag  = flag.String("go-var", "embedded", "variable name of the gosrc output map")
+	shardFlag  = flag.Int("shard-by-prefix", 0, "write the output as a directory of shards by the first N hex digits of the key, plus an index (optional)")
+	quarFile   = flag.String("quarantine", "", "file to write hash-mismatched entries to (optional)")
+	mmFile     = flag.String("metamask", "", "MetaMask method registry / contract-metadata json to import (optional)")
+	updateFile = flag.String("update", "", "existing selector database to merge the input into, only validating new entries (optional)")
+	listFile   = flag.String("list", "", "comma-separated hand-maintained signature lists to import (optional)")
+	abiDir     = flag.String("abi-dir", "", "directory of contract abi json files (plain, Hardhat, Truffle or Foundry) to import (optional)")
+	jobsFlag   = flag.Int("jobs", runtime.NumCPU(), "number of concurrent validation workers")
+	watch      = flag.Bool("watch", false, "after building, keep watching the input directory, and rebuild the output on new or changed signature files")
+	strict     = flag.Bool("strict", false, "fail the build, without writing any output, on any invalid entry (same as -max-errors 0)")
+	maxErrors  = flag.Int("max-errors", -1, "fail the build, without writing any output, if more entries are invalid (negative for no limit)")
+	lenient    = flag.Bool("lenient", false, "accept signatures with type aliases (uint, int, byte, fixed) or stray spaces, storing their canonical form")
+
+	logFormat  = flag.String("log-format", "text", "format of the log output (text or json)")
+	quiet      = flag.Bool("quiet", false, "only log errors")
+	verbose    = flag.Bool("v", false, "also log debug messages, such as every added entry")
+	reportFile = flag.String("report", "", "file to write a json report of the build statistics to (optional)")
+
+	collisionFlag = flag.String("collisions", "first", "policy for colliding signatures: "+strings.Join(collisionPolicies, ", "))
+	priorityFile  = flag.String("priority", "", "signatures to prefer with -collisions prefer-list, one per line, best first")
+	collReport    = flag.String("collision-report", "", "file to write all colliding signatures and the choices made to (optional)")
+
+	check       = flag.Bool("check", false, "only validate the input, reporting all offending entries, and exit non-zero if any")
+	checkFormat = flag.String("check-format", "json", "format of the -check report (json or tsv)")
+	checkReport = flag.String("check-report", "", "file to write the -check report to (default stdout)")
+
+	inputFormat    = flag.String("input-format", "auto", "comma-separated format of each input: "+strings.Join(inputFormats, ", "))
+	conflictReport = flag.String("conflict-report", "", "file to write the entries several inputs disagree on to (optional)")
+
+	fetch        = flag.Bool("fetch", false, "fetch new signatures from the 4byte.directory api (the only api supported) into the packed file given by -i first")
+	fetchURL     = flag.String("fetch-url", defaultFetchURL, "first api page to fetch, when not resuming")
+	fetchDelay   = flag.Duration("fetch-delay", time.Second, "delay between api requests")
+	fetchRetries = flag.Int("fetch-retries", 5, "number of retries per api request")
+
+	manifestOut  = flag.String("manifest", "", "file to write a manifest of the output files, with their hashes, to (optional)")
+	sourceFlag   = flag.String("source", "", "source commit or url recorded in the manifest (default: the git commit of the input, or the fetch url)")
+	signKey      = flag.String("sign-key", "", "hex private key file to sign the manifest with (optional)")
+	keystoreFile = flag.String("keystore", "", "keystore file to sign the manifest with (optional)")
+	passwordFile = flag.String("password", "", "password file of the -keystore")
 )
 
 func init() {
 	flag.Usage = func() {
 		fmt.Fprintln(os.Stderr, "Usage:", os.Args[0], "-i directory -o outputfile")
+		fmt.Fprintln(os.Stderr, "       "+os.Args[0], "pack -i directory -o packedfile")
+		fmt.Fprintln(os.Stderr, "       "+os.Args[0], "query -db database [-calldata hex] [selector...]")
+		fmt.Fprintln(os.Stderr, "       "+os.Args[0], "fingerprint -code bytecodefile [-db database] [-known file] [-rpc url]")
+		fmt.Fprintln(os.Stderr, "       "+os.Args[0], "verify -manifest manifest [-signer address]")
+		fmt.Fprintln(os.Stderr, "       "+os.Args[0], "serve -db database [-addr address] [-reload] [-compact]")
 		flag.PrintDefaults()
-		fmt.Fprintln(os.Stderr, `
+		fmt.Fprint(os.Stderr, `
 This is a little helper-utility to collect the data from
 https://github.com/ethereum-lists/4bytes and massage it into a
 clef-digestable format.
 
 It parses the signatures from the given directory, and writes
-them to the given outputfile as a json struct.
+them to the given outputfile as a json struct. Event signatures,
+keyed by their 32-byte topic hash, can be written to a separate
+output file. Besides json, the output can be yaml, or a compact binary
+encoding for embedding, which clef reads with the bindb package.
+Indexers can use -format sqlite instead, a database with a selectors
+(or events) table of sig, signature, source and added_at columns.
+
+Selectors claimed by several signatures are resolved by -collisions:
+use the first one, keep all valid ones, ask, or prefer the signatures
+listed in a -priority file. The choices can be audited with
+-collision-report.
+
+Instead of the directory, a packed file of selector:signature lines
+can be given as input. The pack command creates one from a directory,
+which is a lot faster to sync than the individual files. With -fetch,
+the packed file is created or updated from the 4byte.directory api,
+so no local checkout of the 4bytes repository is needed at all. That
+is the only api supported, openchain.xyz data can be merged from its
+csv exports instead.
+
+Several inputs can be merged, including openchain.xyz csv exports of
+selector,signature lines. The first input claiming a selector wins,
+disagreeing ones are reported with -conflict-report.
+
+Invalid entries are logged and dropped. The build still succeeds, but
+exits with status 2 if anything was dropped, or fails without writing
+any output if more than -max-errors entries were invalid, or any with
+-strict.
+
+Signatures using type aliases, like transfer(address,uint), fail the
+hash check as claimed. With -lenient, their canonical form is checked
+instead, and stored if it matches.
+
+While adding entries to a local checkout, -watch keeps running after
+the build, validating new or changed signature files as they appear
+and rewriting the output and reports. Removed files are only dropped
+by a full rebuild. With -strict or -max-errors, a batch of changes with
+too many invalid entries leaves the output as it was.
+
+Private contracts can be added from their abi json files or compiler
+artifacts with -abi-dir.
+
+With -check, the input is only validated, and a report of all invalid
+entries is written instead of a database, for gating contributions.
+
+The query command looks selectors up in a built database, and decodes
+the arguments of full calldata. ERC-4337 handleOps bundles are
+unwrapped, and the call of each user operation is decoded as well.
+The serve command answers the same over http, with GET
+/selector/{selector} and POST /decode of {"calldata": "0x..."}, and
+with -reload picks up rebuilt databases while running. With -compact,
+the signatures are read straight into interned token sequences, which
+takes about a fifth of the memory.
 
 Afterwards, you can do
 
    [cmd/clef]$ go-bindata resources
 
-To generatee the bindata.go asset file.
+To generatee the bindata.go asset file. Alternatively, -format gosrc
+writes a Go source file declaring the map directly, which needs no
+further step.
+
+For lazy loading, -shard-by-prefix N writes the output into the -o
+directory instead, split into 16^N files by selector prefix, plus an
+index.json manifest.
+
+A -manifest lists the hashes and entry counts of all output files, and
+can be signed with -sign-key or -keystore. The verify command checks an
+embedded database against it, and with -signer who signed it.
+
+Progress and problems are logged to stderr, as text or, with
+-log-format json, one json object per line. A summary of the build is
+logged at the end, and can be saved as json with -report.
 `)
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "pack" {
+		if err := runPack(os.Args[2:]); err != nil {
+			log.Crit("Failed to pack data", "err", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		if err := runVerify(os.Args[2:]); err != nil {
+			log.Crit("Failed to verify manifest", "err", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "query" {
+		if err := runQuery(os.Args[2:]); err != nil {
+			log.Crit("Failed to query database", "err", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fingerprint" {
+		if err := runFingerprint(os.Args[2:]); err != nil {
+			log.Crit("Failed to fingerprint bytecode", "err", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:]); err != nil {
+			log.Crit("Failed to serve database", "err", err)
+		}
+		return
+	}
 	flag.Parse()
-	in := *inDir
+	if err := setupLogging(*logFormat, *quiet, *verbose); err != nil {
+		log.Crit("Invalid arguments", "err", err)
+	}
 	out := *outFile
-	if in == "" {
-		fmt.Fprintf(os.Stderr, "input directory not given\n")
-		os.Exit(1)
+	if *inDir == "" {
+		log.Crit("Input directory not given")
+	}
+	inputs, err := parseInputs(*inDir, *inputFormat)
+	if err != nil {
+		log.Crit("Invalid arguments", "err", err)
+	}
+	switch *keyCase {
+	case "lower", "upper":
+		outputKeys = abidb.KeyFormat{Prefix: *keyPrefix, Upper: *keyCase == "upper"}
+	default:
+		log.Crit("Invalid key case", "case", *keyCase)
+	}
+	if *jobsFlag < 1 {
+		log.Crit("Invalid number of jobs", "jobs", *jobsFlag)
+	}
+	jobs = *jobsFlag
+	if !token.IsIdentifier(*goPkgFlag) {
+		log.Crit("Invalid Go package name", "package", *goPkgFlag)
+	}
+	if !token.IsIdentifier(*goVarFlag) {
+		log.Crit("Invalid Go variable name", "var", *goVarFlag)
+	}
+	goPackage, goVar = *goPkgFlag, *goVarFlag
+	if *shardFlag < 0 || *shardFlag > maxShardPrefix {
+		log.Crit("Invalid shard prefix length", "length", *shardFlag, "max", maxShardPrefix)
+	}
+	shardPrefix = *shardFlag
+	if *check {
+		problems, err := runCheck(inputs, *checkFormat, *checkReport, *lenient)
+		if err != nil {
+			log.Crit("Failed to check input", "err", err)
+		}
+		if problems > 0 {
+			os.Exit(1)
+		}
+		return
 	}
 	if out == "" {
-		fmt.Fprintf(os.Stderr, "output file not given\n")
-		os.Exit(1)
+		log.Crit("Output file not given")
 	}
-	data, err := readFiles(in)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "error reading data: %v\n", err)
-		os.Exit(1)
+	targets, err := parseTargets(*formats, out)
+	if err == nil {
+		err = checkShardable(targets)
 	}
-	err = dumpData(data, out)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "error writing data: %v\n", err)
-		os.Exit(1)
+		log.Crit("Invalid arguments", "err", err)
 	}
-}
-
-func dumpData(db *orderedmap.OrderedMap, outfile string) error {
-	fmt.Println("Sorting data...")
-	db.Sort(func(a *orderedmap.Pair, b *orderedmap.Pair) bool {
-		return a.Key() < b.Key()
-	})
-	fmt.Println("Marshalling data...")
-	data, err := json.MarshalIndent(db, "", "")
-	if err != nil {
-		return err
+	var eventTargets []target
+	if *eventsFile != "" {
+		if eventTargets, err = parseTargets(*formats, *eventsFile); err != nil {
+			log.Crit("Invalid arguments", "err", err)
+		}
 	}
-	fmt.Println("Saving data to %v...", outfile)
-	return ioutil.WriteFile(outfile, data, 0644)
-
-}
-func testSelector(selector string, id []byte) error {
-	abistring, err := parseSelector(selector)
-	if err != nil {
-		return err
+	if collisions, err = newCollisionPolicy(*collisionFlag, *priorityFile); err != nil {
+		log.Crit("Invalid arguments", "err", err)
 	}
-	abistruct, err := abi.JSON(strings.NewReader(string(abistring)))
+	signer, err := loadSigningKey(*signKey, *keystoreFile, *passwordFile)
 	if err != nil {
-		return err
+		log.Crit("Failed to load signing key", "err", err)
 	}
-	m, err := abistruct.MethodById(id)
-	if err != nil {
-		return err
+	if *watch && !isDir(inputs) {
+		log.Crit("Watching needs a single input directory")
 	}
-	if m.Sig != selector {
-		return fmt.Errorf("Expected equality: %v != %v", m.Sig, selector)
+	if *fetch {
+		if len(inputs) != 1 {
+			log.Crit("Fetching needs a single packed input file")
+		}
+		f := &fetcher{client: &http.Client{Timeout: time.Minute}, delay: *fetchDelay, retries: *fetchRetries}
+		if err := f.fetch(*fetchURL, inputs[0].path); err != nil {
+			log.Crit("Failed to fetch data", "err", err)
+		}
 	}
-	return nil
-}
-func readFiles(dir string) (*orderedmap.OrderedMap, error) {
-	f, err := os.Open(dir)
-	if err != nil {
-		log.Fatal(err)
+	b := abidb.NewBuilder()
+	if *lenient {
+		b.Lenient()
 	}
-	files, err := f.Readdir(-1)
-	f.Close()
-	if err != nil {
-		return nil, err
+	var existing *abidb.Table
+	if *updateFile != "" {
+		if existing, err = loadUpdate(*updateFile); err != nil {
+			log.Crit("Failed to read database to update", "err", err)
+		}
+		b.Trust(existing)
 	}
-	db := orderedmap.New()
-	for _, file := range files {
-		// Only bother with signature files
-		sig, err := hex.DecodeString(file.Name())
-		if err != nil {
-			continue
+	if len(inputs) > 1 {
+		merge = newCrossMerge()
+	}
+	for _, in := range inputs {
+		if err := readInput(in, b, func(r *record) { r.commit(b) }); err != nil {
+			log.Crit("Failed to read data", "err", err)
 		}
-		if len(sig) != 4 {
-			fmt.Printf("Invalid sig, wrong length: %x", sig)
+	}
+	if merge != nil {
+		log.Info("Merged inputs", "inputs", len(inputs), "duplicates", merge.duplicates, "conflicts", len(merge.conflicts))
+	}
+	if *abiDir != "" {
+		if err := importABIDir(*abiDir, b); err != nil {
+			log.Crit("Failed to import abi files", "err", err)
 		}
-		dat, err := ioutil.ReadFile(fmt.Sprintf("%s/%s", dir, file.Name()))
-		if err != nil {
-			fmt.Printf("err reading file: %v\n", err)
-			continue
-		}
-		selectors := strings.Split(string(dat), ";")
-		if len(selectors) > 1 {
-			fmt.Printf("sig `%x`\n", sig)
-			for _, selector := range selectors {
-				fmt.Printf(" - %v\n", selector)
+	}
+	if *mmFile != "" {
+		if err := importMetaMask(*mmFile, b); err != nil {
+			log.Crit("Failed to import metamask data", "err", err)
+		}
+	}
+	if *listFile != "" {
+		for _, file := range strings.Split(*listFile, ",") {
+			if err := importList(file, b); err != nil {
+				log.Crit("Failed to import list", "err", err)
+			}
+		}
+	}
+	// reports writes the reports on the entries read so far
+	reports := func() error {
+		if *conflictReport != "" {
+			if err := writeConflictReport(*conflictReport); err != nil {
+				return fmt.Errorf("writing conflict report: %v", err)
+			}
+		}
+		if *collReport != "" {
+			if err := collisions.writeReport(*collReport); err != nil {
+				return fmt.Errorf("writing collision report: %v", err)
+			}
+		}
+		if *quarFile != "" {
+			if err := writeQuarantine(b.Mismatches(), *quarFile); err != nil {
+				return fmt.Errorf("writing quarantine: %v", err)
+			}
+		}
+		return nil
+	}
+	if err := reports(); err != nil {
+		log.Crit("Failed to write report", "err", err)
+	}
+	// finish logs the build summary, and saves the report if requested
+	finish := func() {
+		report.finish(inputs, b)
+		if *reportFile != "" {
+			if err := report.write(*reportFile); err != nil {
+				log.Crit("Failed to write build report", "err", err)
 			}
-			fmt.Println(" -- using first one\n")
 		}
-		selector := strings.TrimSpace(selectors[0])
-		if err = testSelector(selector, sig); err != nil {
-			fmt.Printf("Bad selector: %v, err: %v\n", selector, err)
-			continue
+	}
+	limit := *maxErrors
+	if *strict {
+		limit = 0
+	}
+	dropped := report.rejected()
+	if limit >= 0 && dropped > limit {
+		finish()
+		log.Crit("Too many invalid entries, not writing output", "invalid", dropped, "max", limit)
+	}
+	data := b.Database()
+	if existing != nil {
+		mergeUpdate(existing, data.Methods, dropped)
+	}
+	source := *sourceFlag
+	switch {
+	case source != "":
+	case *fetch:
+		source = *fetchURL
+	case len(inputs) == 1 && *manifestOut != "":
+		if source, err = gitSource(inputs[0].path); err != nil {
+			log.Warn("Failed to determine git commit of input", "err", err)
+		}
+	}
+	// emit writes all outputs of the database built so far
+	emit := func() error {
+		if err := writeTable(data.Methods, targets, out); err != nil {
+			return fmt.Errorf("writing data: %v", err)
 		}
-		// We do a basic sanity check here, not fully verifying the correctness of
-		// arguments, e.g the parameter types. We assume that the 4byte db comes
-		// from a somewhat trusted source
-		want := crypto.Keccak256([]byte(selector))[:4]
-		if !bytes.Equal(sig, want) {
-			fmt.Printf("Erroneous selector: %s, have %x want %x", selector, sig, want)
-			continue
+		if eventTargets != nil {
+			if err := writeTable(data.Events, eventTargets, *eventsFile); err != nil {
+				return fmt.Errorf("writing events: %v", err)
+			}
+		} else if n := data.Events.Len(); n > 0 {
+			log.Info("Found event signatures, use -events to save them", "events", n)
 		}
-		db.Set(fmt.Sprintf("%x", sig), selector)
+		if *manifestOut != "" {
+			files := outputFiles(data.Methods, targets, out)
+			if eventTargets != nil {
+				files = append(files, outputFiles(data.Events, eventTargets, *eventsFile)...)
+			}
+			if err := writeManifest(*manifestOut, source, files, signer); err != nil {
+				return fmt.Errorf("writing manifest: %v", err)
+			}
+		}
+		return nil
+	}
+	if err := emit(); err != nil {
+		log.Crit("Failed to write output", "err", err)
+	}
+	finish()
+	if *watch {
+		// Rebuilds go through the same steps, but a rebuild over the limit
+		// only keeps the previous output, the next one may fix it.
+		err := watchDir(inputs[0].path, b, func(rejected int) error {
+			if err := reports(); err != nil {
+				return err
+			}
+			if limit >= 0 && rejected > limit {
+				finish()
+				log.Error("Too many invalid entries, not writing output", "invalid", rejected, "max", limit)
+				return nil
+			}
+			if err := emit(); err != nil {
+				return err
+			}
+			finish()
+			return nil
+		})
+		log.Crit("Failed to watch input", "err", err)
+	}
+	if dropped > 0 {
+		os.Exit(2)
 	}
-	return db, nil
 }
 
-// selectorRegexp is used to validate that a 4byte database selector corresponds
-// to a valid ABI function declaration.
-//
-// Note, although uppercase letters are not part of the ABI spec, this regexp
-// still accepts it as the general format is valid. It will be rejected later
-// by the type checker.
-var selectorRegexp = regexp.MustCompile(`^([^\)]+)\(([A-Za-z0-9,\[\]]*)\)`)
-
-// parseSelector converts a method selector into an ABI JSON spec. The returned
-// data is a valid JSON string which can be consumed by the standard abi package.
-func parseSelector(selector string) ([]byte, error) {
-	// Define a tiny fake ABI struct for JSON marshalling
-	type fakeArg struct {
-		Type string `json:"type"`
-	}
-	type fakeABI struct {
-		Name   string    `json:"name"`
-		Type   string    `json:"type"`
-		Inputs []fakeArg `json:"inputs"`
-	}
-	// Validate the selector and extract it's components
-	groups := selectorRegexp.FindStringSubmatch(selector)
-	if len(groups) != 3 {
-		return nil, fmt.Errorf("invalid selector %s (%v matches)", selector, len(groups))
-	}
-	name := groups[1]
-	args := groups[2]
-
-	// Reassemble the fake ABI and constuct the JSON
-	arguments := make([]fakeArg, 0)
-	if len(args) > 0 {
-		for _, arg := range strings.Split(args, ",") {
-			arguments = append(arguments, fakeArg{arg})
-		}
-	}
-	return json.Marshal([]fakeABI{{name, "function", arguments}})
+// readFiles reads all signature files from the given directory, and passes
+// them to commit once validated.
+func readFiles(dir string, b *abidb.Builder, commit func(*record)) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	files, err := f.Readdir(-1)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	// Keep the commit order, and thus all output, independent of the filesystem
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return processRecords(b, int64(len(files)), func(emit func(*record)) error {
+		for _, file := range files {
+			emit(fileRecord(dir, file.Name(), b))
+		}
+		return nil
+	}, commit)
+}
+
+// fileRecord creates the record of a signature file in dir.
+func fileRecord(dir, name string, b *abidb.Builder) *record {
+	// Only bother with signature files
+	sig, err := abidb.ParseKey(name)
+	if err != nil {
+		return &record{skip: true, size: 1}
+	}
+	if b.Database().Table(sig) == nil {
+		return &record{key: sig, source: name, msg: "Invalid sig, wrong length", kind: "wrong-length", size: 1}
+	}
+	return &record{key: sig, file: fmt.Sprintf("%s/%s", dir, name), source: name, keepFirst: true, size: 1}
 }
@@ -0,0 +1,178 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package bindb reads the compact binary signature database written by
+// abidbbuilder with -format binary.
+//
+// The format is meant for embedding: it is a fraction of the size of the json
+// database, and signatures are looked up directly in the encoded data, so
+// opening it needs no parsing or allocations at all. The package has no
+// dependencies outside the standard library. The encoding, with all integers
+// big-endian, is
+//
+//	magic     "4bdb"
+//	version   1 byte, currently 1
+//	key size  1 byte, 4 for method selectors or 32 for event topics
+//	count     4 bytes
+//	keys      count * key size bytes, in ascending order
+//	ends      count * 4 bytes, end offset of each signature in the blob
+//	blob      all signatures concatenated
+//
+// If the database was built keeping all colliding signatures, the signature
+// of such a key is the ;-separated list of them.
+package bindb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Magic is the prefix identifying a binary database.
+const Magic = "4bdb"
+
+// version is the current version of the encoding.
+const version = 1
+
+// headerSize is the length of the fixed-size header.
+const headerSize = len(Magic) + 2 + 4
+
+// DB is a decoded binary database. It references the encoded data, which must
+// not be modified as long as the DB is in use.
+type DB struct {
+	keySize int
+	count   int
+	keys    []byte
+	ends    []byte
+	blob    []byte
+}
+
+// Open validates the encoded data, and returns a database backed by it.
+func Open(data []byte) (*DB, error) {
+	if len(data) < headerSize || string(data[:len(Magic)]) != Magic {
+		return nil, fmt.Errorf("not a binary signature database")
+	}
+	if v := data[len(Magic)]; v != version {
+		return nil, fmt.Errorf("unsupported version %d", v)
+	}
+	db := &DB{
+		keySize: int(data[len(Magic)+1]),
+		count:   int(binary.BigEndian.Uint32(data[len(Magic)+2:])),
+	}
+	if db.keySize == 0 {
+		return nil, fmt.Errorf("invalid key size 0")
+	}
+	data = data[headerSize:]
+	if uint64(len(data)) < uint64(db.count)*uint64(db.keySize+4) {
+		return nil, fmt.Errorf("truncated database, %d bytes for %d entries", len(data), db.count)
+	}
+	db.keys, data = data[:db.count*db.keySize], data[db.count*db.keySize:]
+	db.ends, db.blob = data[:db.count*4], data[db.count*4:]
+
+	// Check the offsets once, so lookups can rely on them
+	var prev uint32
+	for i := 0; i < db.count; i++ {
+		end := binary.BigEndian.Uint32(db.ends[i*4:])
+		if end < prev || uint64(end) > uint64(len(db.blob)) {
+			return nil, fmt.Errorf("invalid offset %d for entry %d", end, i)
+		}
+		prev = end
+	}
+	if int(prev) != len(db.blob) {
+		return nil, fmt.Errorf("%d trailing bytes", len(db.blob)-int(prev))
+	}
+	return db, nil
+}
+
+// KeySize returns the length of the keys, 4 for selectors or 32 for topics.
+func (db *DB) KeySize() int {
+	return db.keySize
+}
+
+// Len returns the number of entries.
+func (db *DB) Len() int {
+	return db.count
+}
+
+// Key returns the i'th key, in ascending order.
+func (db *DB) Key(i int) []byte {
+	return db.keys[i*db.keySize : (i+1)*db.keySize]
+}
+
+// Signature returns the signature of the i'th key.
+func (db *DB) Signature(i int) string {
+	var start uint32
+	if i > 0 {
+		start = binary.BigEndian.Uint32(db.ends[(i-1)*4:])
+	}
+	return string(db.blob[start:binary.BigEndian.Uint32(db.ends[i*4:])])
+}
+
+// Lookup returns the signature for the given selector or topic.
+func (db *DB) Lookup(key []byte) (string, bool) {
+	if len(key) != db.keySize {
+		return "", false
+	}
+	i := sort.Search(db.count, func(i int) bool {
+		return bytes.Compare(db.Key(i), key) >= 0
+	})
+	if i == db.count || !bytes.Equal(db.Key(i), key) {
+		return "", false
+	}
+	return db.Signature(i), true
+}
+
+// Encode writes the given entries in the binary format. All keys must have the
+// given size, and be sorted in ascending order without duplicates.
+func Encode(w io.Writer, keySize int, keys [][]byte, signatures []string) (int64, error) {
+	if keySize < 1 || keySize > 255 {
+		return 0, fmt.Errorf("invalid key size %d", keySize)
+	}
+	if len(keys) != len(signatures) {
+		return 0, fmt.Errorf("have %d keys but %d signatures", len(keys), len(signatures))
+	}
+	var blobSize int
+	for _, sig := range signatures {
+		blobSize += len(sig)
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, headerSize+len(keys)*(keySize+4)+blobSize))
+	buf.WriteString(Magic)
+	buf.WriteByte(version)
+	buf.WriteByte(byte(keySize))
+	binary.Write(buf, binary.BigEndian, uint32(len(keys)))
+	for i, key := range keys {
+		if len(key) != keySize {
+			return 0, fmt.Errorf("invalid key %x, want %d bytes", key, keySize)
+		}
+		if i > 0 && bytes.Compare(keys[i-1], key) >= 0 {
+			return 0, fmt.Errorf("keys out of order: %x after %x", key, keys[i-1])
+		}
+		buf.Write(key)
+	}
+	var end uint64
+	for _, sig := range signatures {
+		if end += uint64(len(sig)); end > 1<<32-1 {
+			return 0, fmt.Errorf("signatures exceed 4GB")
+		}
+		binary.Write(buf, binary.BigEndian, uint32(end))
+	}
+	for _, sig := range signatures {
+		buf.WriteString(sig)
+	}
+	return buf.WriteTo(w)
+}
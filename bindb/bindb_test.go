@@ -0,0 +1,152 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package bindb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+var (
+	testKeys = [][]byte{
+		{0x06, 0xfd, 0xde, 0x03},
+		{0x70, 0xa0, 0x82, 0x31},
+		{0xa9, 0x05, 0x9c, 0xbb},
+	}
+	testSignatures = []string{
+		"name()",
+		"balanceOf(address)",
+		"transfer(address,uint256);many_msg_babbage(bytes1)",
+	}
+)
+
+// encode encodes the test entries.
+func encode(t *testing.T) []byte {
+	var buf bytes.Buffer
+	n, err := Encode(&buf, 4, testKeys, testSignatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Fatalf("reported %d bytes written, have %d", n, buf.Len())
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	db, err := Open(encode(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != len(testKeys) || db.KeySize() != 4 {
+		t.Fatalf("have %d entries of %d bytes", db.Len(), db.KeySize())
+	}
+	for i, key := range testKeys {
+		if !bytes.Equal(db.Key(i), key) {
+			t.Errorf("key %d: have %x, want %x", i, db.Key(i), key)
+		}
+		sig, ok := db.Lookup(key)
+		if !ok || sig != testSignatures[i] {
+			t.Errorf("lookup %x: have %q (%v), want %q", key, sig, ok, testSignatures[i])
+		}
+	}
+	for _, key := range [][]byte{{0, 0, 0, 0}, {0x70, 0xa0, 0x82, 0x32}, {0xff, 0xff, 0xff, 0xff}, {0x06, 0xfd, 0xde}} {
+		if sig, ok := db.Lookup(key); ok {
+			t.Errorf("lookup %x: found %q", key, sig)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Encode(&buf, 32, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Len() != 0 || db.KeySize() != 32 {
+		t.Fatalf("have %d entries of %d bytes", db.Len(), db.KeySize())
+	}
+	if _, ok := db.Lookup(make([]byte, 32)); ok {
+		t.Error("lookup in empty database succeeded")
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	data := encode(t)
+	for n := 0; n < len(data); n++ {
+		if _, err := Open(data[:n]); err == nil {
+			t.Errorf("truncated to %d of %d bytes: accepted", n, len(data))
+		}
+	}
+	if _, err := Open(append(append([]byte{}, data...), 'x')); err == nil {
+		t.Error("trailing byte accepted")
+	}
+}
+
+func TestBadHeader(t *testing.T) {
+	tests := map[string]func(data []byte){
+		"magic":    func(data []byte) { data[0] = 'x' },
+		"version":  func(data []byte) { data[len(Magic)] = version + 1 },
+		"key size": func(data []byte) { data[len(Magic)+1] = 0 },
+		"count":    func(data []byte) { binary.BigEndian.PutUint32(data[len(Magic)+2:], 1<<31) },
+	}
+	for name, corrupt := range tests {
+		data := encode(t)
+		corrupt(data)
+		if _, err := Open(data); err == nil {
+			t.Errorf("bad %s accepted", name)
+		}
+	}
+}
+
+func TestBadOffsets(t *testing.T) {
+	ends := headerSize + len(testKeys)*4
+	tests := map[string]uint32{
+		"decreasing":  0,       // second end before the first
+		"beyond blob": 1 << 20, // second end past the data
+	}
+	for name, end := range tests {
+		data := encode(t)
+		binary.BigEndian.PutUint32(data[ends+4:], end)
+		if _, err := Open(data); err == nil {
+			t.Errorf("%s offset accepted", name)
+		}
+	}
+}
+
+func TestEncodeInvalid(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Encode(&buf, 4, [][]byte{testKeys[1], testKeys[0]}, testSignatures[:2]); err == nil {
+		t.Error("unsorted keys accepted")
+	}
+	if _, err := Encode(&buf, 4, [][]byte{testKeys[0], testKeys[0]}, testSignatures[:2]); err == nil {
+		t.Error("duplicate keys accepted")
+	}
+	if _, err := Encode(&buf, 4, [][]byte{{1, 2, 3}}, testSignatures[:1]); err == nil {
+		t.Error("short key accepted")
+	}
+	if _, err := Encode(&buf, 4, testKeys, testSignatures[:1]); err == nil {
+		t.Error("missing signatures accepted")
+	}
+	if _, err := Encode(&buf, 0, nil, nil); err == nil {
+		t.Error("zero key size accepted")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// checkIssue is a single problem found in the input by the check mode.
+type checkIssue struct {
+	Source    string `json:"source"`              // file, or packed file and line
+	Selector  string `json:"selector,omitempty"`  // selector or topic claimed
+	Kind      string `json:"kind"`                // category of the problem
+	Signature string `json:"signature,omitempty"` // offending signature, if any
+	Message   string `json:"message"`
+}
+
+// checker collects the problems of all records, instead of building from them.
+type checker struct {
+	issues  []checkIssue
+	seen    map[string]string // signature content to the first source with it
+	entries int
+}
+
+// runCheck validates all entries of the inputs, and writes a report of the
+// offending ones in the given format (json or tsv) to the report file, or to
+// stdout if none is given. If lenient, entries valid in canonical form are not
+// reported. The number of problems found is returned.
+func runCheck(inputs []input, format, reportFile string, lenient bool) (int, error) {
+	if format != "json" && format != "tsv" {
+		return 0, fmt.Errorf("unknown report format %q (available: json, tsv)", format)
+	}
+	c := &checker{issues: []checkIssue{}}
+	for _, in := range inputs {
+		// Different sources agreeing on an entry is fine
+		c.seen = make(map[string]string)
+		b := abidb.NewBuilder()
+		if lenient {
+			b.Lenient()
+		}
+		if err := readInput(in, b, c.check); err != nil {
+			return 0, err
+		}
+	}
+	var w io.Writer = os.Stdout
+	if reportFile != "" {
+		f, err := os.Create(reportFile)
+		if err != nil {
+			return 0, err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := c.writeReport(w, format); err != nil {
+		return 0, err
+	}
+	log.Info("Checked input", "entries", c.entries, "problems", len(c.issues))
+	return len(c.issues), nil
+}
+
+// check records the problems of a validated record.
+func (c *checker) check(r *record) {
+	if r.skip {
+		return
+	}
+	c.entries++
+	issue := checkIssue{Source: r.source}
+	if r.key != nil {
+		issue.Selector = fmt.Sprintf("%x", r.key)
+	}
+	switch {
+	case r.msg != "":
+		issue.Kind, issue.Message = r.kind, r.msg
+		for i := 1; i < len(r.ctx); i += 2 {
+			issue.Message += fmt.Sprintf(", %v: %v", r.ctx[i-1], r.ctx[i])
+		}
+		c.issues = append(c.issues, issue)
+		return
+	case r.err != nil:
+		issue.Kind, issue.Message = "unreadable", r.err.Error()
+		c.issues = append(c.issues, issue)
+		return
+	}
+	content := strings.TrimSpace(r.data)
+	if first, ok := c.seen[content]; ok {
+		issue.Kind, issue.Message = "duplicate", "same content as "+first
+		c.issues = append(c.issues, issue)
+	} else {
+		c.seen[content] = r.source
+	}
+	listed := make(map[string]bool)
+	for _, checked := range r.checked {
+		issue := issue
+		issue.Signature = checked.Entry.Signature
+		switch {
+		case listed[checked.Entry.Signature]:
+			issue.Kind, issue.Message = "duplicate", "signature listed twice"
+		case checked.Err == nil:
+			listed[checked.Entry.Signature] = true
+			continue
+		default:
+			issue.Kind, issue.Message = rejectReason(checked.Err), checked.Err.Error()
+		}
+		listed[checked.Entry.Signature] = true
+		c.issues = append(c.issues, issue)
+	}
+}
+
+// writeReport writes the collected problems as a json list, or as tab separated
+// values with a header line.
+func (c *checker) writeReport(w io.Writer, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(c.issues, "", " ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	if _, err := fmt.Fprintln(w, "source\tselector\tkind\tsignature\tmessage"); err != nil {
+		return err
+	}
+	for _, issue := range c.issues {
+		fields := []string{issue.Source, issue.Selector, issue.Kind, issue.Signature, issue.Message}
+		for i, field := range fields {
+			fields[i] = clean.Replace(field)
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"clean/a9059cbb": "transfer(address,uint256)",
+		"dirty/a9059cbb": "transfer(address,uint256)",
+		"dirty/deadbeef": "notDeadBeef(uint256)",
+		"dirty/abcd":     "short()",
+	})
+	tests := []struct {
+		input    string
+		format   string
+		problems int
+		want     string
+	}{
+		{"clean", "json", 0, "[]\n"},
+		{"clean", "tsv", 0, "source\tselector\tkind\tsignature\tmessage\n"},
+		{"dirty", "json", 2, `"kind": "hash-mismatch"`},
+		{"dirty", "tsv", 2, "deadbeef\tdeadbeef\thash-mismatch\tnotDeadBeef(uint256)\t"},
+	}
+	for _, tt := range tests {
+		report := filepath.Join(dir, "report")
+		inputs := []input{{path: filepath.Join(dir, tt.input), format: "auto"}}
+		problems, err := runCheck(inputs, tt.format, report, false)
+		if err != nil {
+			t.Fatalf("%s/%s: %v", tt.input, tt.format, err)
+		}
+		if problems != tt.problems {
+			t.Errorf("%s/%s: have %d problems, want %d", tt.input, tt.format, problems, tt.problems)
+		}
+		data, err := ioutil.ReadFile(report)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := string(data); (tt.problems == 0 && have != tt.want) || !strings.Contains(have, tt.want) {
+			t.Errorf("%s/%s: report %q, want %q", tt.input, tt.format, have, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// collisionPolicies lists the supported ways to resolve multiple signatures
+// claimed for the same selector.
+var collisionPolicies = []string{"first", "all", "interactive", "prefer-list"}
+
+// collisions is the policy used for all colliding signatures in the input.
+var collisions = &collisionPolicy{name: "first", report: []collision{}}
+
+// collisionPolicy resolves colliding signatures into the entry to store, and
+// records every collision for the report.
+type collisionPolicy struct {
+	name     string
+	priority map[string]int // signature ranks of the prefer-list policy
+	input    *bufio.Reader  // answers of the interactive policy
+	report   []collision
+	reported map[string]int // report index of each selector
+}
+
+// collision is a single report entry, a selector or topic claimed by several
+// signatures.
+type collision struct {
+	Selector   string               `json:"selector"`
+	Candidates []collisionCandidate `json:"candidates"`
+	Chosen     []string             `json:"chosen"` // signatures stored, if any
+}
+
+// collisionCandidate is one of the colliding signatures.
+type collisionCandidate struct {
+	Signature string `json:"signature"`
+	Error     string `json:"error,omitempty"` // validation failure, if any
+}
+
+// newCollisionPolicy creates the named policy. The priority file is required
+// by, and only used for, the prefer-list policy.
+func newCollisionPolicy(name string, priorityFile string) (*collisionPolicy, error) {
+	p := &collisionPolicy{name: name, report: []collision{}}
+	switch name {
+	case "first", "all":
+	case "interactive":
+		p.input = bufio.NewReader(os.Stdin)
+	case "prefer-list":
+		if priorityFile == "" {
+			return nil, fmt.Errorf("collision policy prefer-list needs a priority file")
+		}
+		var err error
+		if p.priority, err = loadPriority(priorityFile); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown collision policy %q (available: %v)", name, strings.Join(collisionPolicies, ", "))
+	}
+	return p, nil
+}
+
+// loadPriority reads a priority file: one signature per line, the earlier ones
+// preferred over the later ones. Everything after a '#' is a comment.
+func loadPriority(file string) (map[string]int, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	priority := make(map[string]int)
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if _, ok := priority[line]; !ok {
+			priority[line] = len(priority)
+		}
+	}
+	return priority, nil
+}
+
+// resolve picks the entry to store from the checked candidates of a collision,
+// and logs the choice. If no valid candidate is chosen, the first one is
+// returned, so its rejection gets recorded.
+func (p *collisionPolicy) resolve(key []byte, candidates []*abidb.Checked) *abidb.Checked {
+	var (
+		report  = collision{Selector: fmt.Sprintf("%x", key)}
+		valid   []*abidb.Checked
+		invalid []string
+		seen    = make(map[string]bool)
+	)
+	for _, c := range candidates {
+		cand := collisionCandidate{Signature: c.Entry.Signature}
+		switch {
+		case c.Err != nil:
+			cand.Error = c.Err.Error()
+			invalid = append(invalid, c.Entry.Signature)
+		case !seen[c.Entry.Signature]:
+			// Repeated signatures are only choices once
+			seen[c.Entry.Signature] = true
+			valid = append(valid, c)
+		}
+		report.Candidates = append(report.Candidates, cand)
+	}
+	chosen, how := p.choose(key, candidates, valid)
+	if chosen.Err == nil {
+		report.Chosen = chosen.Entry.Signatures()
+	}
+	p.record(report)
+
+	var sigs []string
+	for _, c := range valid {
+		sigs = append(sigs, c.Entry.Signature)
+	}
+	log.Info("Resolved colliding signatures", "sig", report.Selector, "valid", strings.Join(sigs, ";"),
+		"invalid", strings.Join(invalid, ";"), "chosen", strings.Join(report.Chosen, ";"), "by", how)
+	return chosen
+}
+
+// record adds a collision to the report. A selector resolved again, as it was
+// listed several times, keeps a single report entry with all candidates.
+func (p *collisionPolicy) record(c collision) {
+	if p.reported == nil {
+		p.reported = make(map[string]int)
+	}
+	i, ok := p.reported[c.Selector]
+	if !ok {
+		p.reported[c.Selector] = len(p.report)
+		p.report = append(p.report, c)
+		return
+	}
+	prev := &p.report[i]
+	for _, cand := range c.Candidates {
+		if !containsCandidate(prev.Candidates, cand) {
+			prev.Candidates = append(prev.Candidates, cand)
+		}
+	}
+	prev.Chosen = c.Chosen
+}
+
+func containsCandidate(list []collisionCandidate, c collisionCandidate) bool {
+	for _, item := range list {
+		if item == c {
+			return true
+		}
+	}
+	return false
+}
+
+// choose applies the policy to the candidates, of which the valid ones are
+// passed separately. Apart from the choice, it returns how it was made.
+func (p *collisionPolicy) choose(key []byte, candidates, valid []*abidb.Checked) (*abidb.Checked, string) {
+	switch {
+	case p.name == "first":
+		return candidates[0], "first"
+	case len(valid) == 0:
+		return candidates[0], "none valid"
+	case len(valid) == 1:
+		return valid[0], "only valid"
+	}
+	switch p.name {
+	case "all":
+		return p.keepAll(valid), "all"
+	case "prefer-list":
+		best := -1
+		for i, c := range valid {
+			rank, ok := p.priority[c.Entry.Signature]
+			if ok && (best < 0 || rank < p.priority[valid[best].Entry.Signature]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			return valid[0], "first valid, none listed"
+		}
+		return valid[best], "listed"
+	default: // interactive
+		fmt.Printf("sig `%x`\n", key)
+		for i, c := range valid {
+			fmt.Printf(" - %d: %v\n", i+1, c.Entry.Signature)
+		}
+		for {
+			fmt.Printf(" -- choose 1-%d, or a for all [1]: ", len(valid))
+			answer, err := p.input.ReadString('\n')
+			answer = strings.TrimSpace(answer)
+			if answer == "" && err == io.EOF {
+				fmt.Println()
+			}
+			if answer == "" {
+				return valid[0], "user"
+			}
+			if answer == "a" {
+				return p.keepAll(valid), "user"
+			}
+			if n, perr := strconv.Atoi(answer); perr == nil && n >= 1 && n <= len(valid) {
+				return valid[n-1], "user"
+			}
+			if err != nil {
+				return valid[0], "user"
+			}
+		}
+	}
+}
+
+// keepAll merges the distinct valid candidates into a single entry, in input
+// order. If only one distinct signature is left, it is returned as is.
+func (p *collisionPolicy) keepAll(valid []*abidb.Checked) *abidb.Checked {
+	c := *valid[0]
+	kept := map[string]bool{c.Entry.Signature: true}
+	for _, other := range valid[1:] {
+		if sig := other.Entry.Signature; !kept[sig] {
+			kept[sig] = true
+			c.Entry.Collisions = append(c.Entry.Collisions, sig)
+		}
+	}
+	if len(c.Entry.Collisions) == 0 {
+		return valid[0]
+	}
+	return &c
+}
+
+// writeReport saves all collisions, sorted by selector, as a json list to the
+// given file.
+func (p *collisionPolicy) writeReport(outfile string) error {
+	report := append([]collision{}, p.report...)
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Selector < report[j].Selector
+	})
+	data, err := json.MarshalIndent(report, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Saving collision report", "collisions", len(report), "file", outfile)
+	return ioutil.WriteFile(outfile, data, 0644)
+}
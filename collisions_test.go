@@ -0,0 +1,83 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// checkAll validates the ;-separated signatures claimed for the selector.
+func checkAll(key []byte, sigs string) []*abidb.Checked {
+	b := abidb.NewBuilder()
+	var checked []*abidb.Checked
+	for _, sig := range strings.Split(sigs, ";") {
+		checked = append(checked, b.Check(key, abidb.Entry{Signature: sig}))
+	}
+	return checked
+}
+
+func TestKeepAllDuplicates(t *testing.T) {
+	tests := []struct {
+		key  []byte
+		sigs string
+		want []string
+	}{
+		// Repeated valid signature next to an invalid one
+		{[]byte{0x09, 0x5e, 0xa7, 0xb3}, "approve(address,uint256);transfer(address,uint256);approve(address,uint256)", []string{"approve(address,uint256)"}},
+		// Repeated colliding signatures
+		{[]byte{0xa9, 0x05, 0x9c, 0xbb}, "transfer(address,uint256);many_msg_babbage(bytes1);transfer(address,uint256);many_msg_babbage(bytes1)", []string{"transfer(address,uint256)", "many_msg_babbage(bytes1)"}},
+	}
+	for _, tt := range tests {
+		p := &collisionPolicy{name: "all"}
+		chosen := p.resolve(tt.key, checkAll(tt.key, tt.sigs))
+		if chosen.Err != nil {
+			t.Fatalf("%s: invalid entry chosen: %v", tt.sigs, chosen.Err)
+		}
+		if have := chosen.Entry.Signatures(); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s: have %v, want %v", tt.sigs, have, tt.want)
+		}
+		if have := p.report[0].Chosen; !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s: reported %v, want %v", tt.sigs, have, tt.want)
+		}
+	}
+}
+
+func TestEmptyCollisionReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	p, err := newCollisionPolicy("all", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "report.json")
+	if err := p.writeReport(file); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(file); string(data) != "[]" {
+		t.Errorf("have %q, want []", data)
+	}
+}
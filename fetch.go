@@ -0,0 +1,194 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// defaultFetchURL is the first page of the 4byte.directory signature listing.
+// Ordering by creation keeps the pagination stable while new signatures are
+// being added, which is what makes resuming from a cursor possible.
+const defaultFetchURL = "https://www.4byte.directory/api/v1/signatures/?ordering=created_at"
+
+// fetchPage is a page of the 4byte.directory signature listing.
+type fetchPage struct {
+	Count   int     `json:"count"`
+	Next    *string `json:"next"`
+	Results []struct {
+		TextSignature string `json:"text_signature"`
+		HexSignature  string `json:"hex_signature"`
+	} `json:"results"`
+}
+
+// fetcher pages through the 4byte.directory REST API, appending all signatures
+// to a packed file. The url of the next page is stored in a cursor file next
+// to it after every page, so an interrupted fetch can be resumed. When all
+// pages are done, the cursor stays at the last page, together with the number
+// of its results already written, so a later fetch appends new signatures
+// only.
+type fetcher struct {
+	client  *http.Client
+	delay   time.Duration // delay between requests, and initial retry backoff
+	retries int           // retries per page before giving up
+}
+
+// fetch updates the packed file with all signatures not fetched yet.
+func (f *fetcher) fetch(start, packed string) error {
+	cursorFile := packed + ".cursor"
+	url, skip := start, 0
+	if data, err := ioutil.ReadFile(cursorFile); err == nil {
+		if url, skip, err = parseCursor(string(data)); err != nil {
+			return fmt.Errorf("invalid cursor file %v: %v", cursorFile, err)
+		}
+		log.Info("Resuming fetch", "url", url, "skip", skip)
+	}
+	out, err := os.OpenFile(packed, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	var fetched int
+	for pages := 0; ; pages++ {
+		if pages > 0 {
+			time.Sleep(f.delay)
+		}
+		page, err := f.fetchPage(url)
+		if err != nil {
+			return err
+		}
+		// Drop the results written by an earlier fetch of the same page
+		results := page.Results
+		if skip > len(results) {
+			skip = len(results)
+		}
+		results = results[skip:]
+
+		var (
+			lines   strings.Builder
+			written int
+		)
+		for _, res := range results {
+			sig, err := abidb.ParseKey(res.HexSignature)
+			if err != nil || strings.ContainsAny(res.TextSignature, "\r\n") {
+				log.Warn("Skipping invalid api entry", "sig", res.HexSignature, "signature", res.TextSignature)
+				continue
+			}
+			fmt.Fprintf(&lines, "%x:%s\n", sig, res.TextSignature)
+			written++
+		}
+		if _, err := out.WriteString(lines.String()); err != nil {
+			return err
+		}
+		if err := out.Sync(); err != nil {
+			return err
+		}
+		fetched += written
+		log.Info("Fetched signatures", "fetched", fetched, "total", page.Count)
+		if page.Next == nil {
+			// Leave the cursor at the last page, new signatures land there
+			return writeCursor(cursorFile, url, skip+len(results))
+		}
+		url, skip = *page.Next, 0
+		if err := writeCursor(cursorFile, url, 0); err != nil {
+			return err
+		}
+	}
+}
+
+// parseCursor splits the contents of a cursor file into the url of the page to
+// fetch next, and the number of its results already written. Cursors holding
+// only the url are from fetches which had not reached the last page yet.
+func parseCursor(cursor string) (string, int, error) {
+	fields := strings.Fields(cursor)
+	switch len(fields) {
+	case 1:
+		return fields[0], 0, nil
+	case 2:
+		skip, err := strconv.Atoi(fields[1])
+		if err != nil || skip < 0 {
+			return "", 0, fmt.Errorf("invalid result count %q", fields[1])
+		}
+		return fields[0], skip, nil
+	}
+	return "", 0, fmt.Errorf("want url and result count")
+}
+
+// writeCursor stores the url of the page to fetch next, and the number of its
+// results already written.
+func writeCursor(file, url string, done int) error {
+	return ioutil.WriteFile(file, []byte(fmt.Sprintf("%s %d\n", url, done)), 0644)
+}
+
+// fetchPage retrieves a single page, retrying with exponential backoff on
+// network errors, rate limiting and server errors.
+func (f *fetcher) fetchPage(url string) (*fetchPage, error) {
+	backoff := f.delay
+	for attempt := 0; ; attempt++ {
+		page, wait, err := f.tryPage(url)
+		if err == nil {
+			return page, nil
+		}
+		if wait < 0 || attempt >= f.retries {
+			return nil, err
+		}
+		if wait < backoff {
+			wait = backoff
+		}
+		log.Warn("Fetching failed, retrying", "url", url, "err", err, "wait", wait)
+		time.Sleep(wait)
+		backoff *= 2
+	}
+}
+
+// tryPage does a single request. On failure, it also returns how long the
+// server asked to wait before retrying, or a negative value if retrying is
+// pointless.
+func (f *fetcher) tryPage(url string) (*fetchPage, time.Duration, error) {
+	res, err := f.client.Get(url)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500:
+		var wait time.Duration
+		if secs, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(secs) * time.Second
+		}
+		return nil, wait, fmt.Errorf("http status %v", res.Status)
+	case res.StatusCode != http.StatusOK:
+		return nil, -1, fmt.Errorf("http status %v", res.Status)
+	}
+	page := new(fetchPage)
+	if err := json.NewDecoder(res.Body).Decode(page); err != nil {
+		return nil, 0, err
+	}
+	return page, 0, nil
+}
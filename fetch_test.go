@@ -0,0 +1,136 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testAPI serves signatures like the 4byte.directory listing, two per page.
+type testAPI struct {
+	lock sync.Mutex
+	sigs []string
+	url  string
+}
+
+func (api *testAPI) add(sigs ...string) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+	api.sigs = append(api.sigs, sigs...)
+}
+
+func (api *testAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	const size = 2
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page == 0 {
+		page = 1
+	}
+	res := map[string]interface{}{"count": len(api.sigs)}
+	var results []map[string]string
+	for i := (page - 1) * size; i < page*size && i < len(api.sigs); i++ {
+		results = append(results, map[string]string{
+			"text_signature": api.sigs[i],
+			"hex_signature":  fmt.Sprintf("0x%08x", i),
+		})
+	}
+	res["results"] = results
+	if page*size < len(api.sigs) {
+		res["next"] = fmt.Sprintf("%s/?page=%d", api.url, page+1)
+	}
+	json.NewEncoder(w).Encode(res)
+}
+
+func TestFetchResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fetch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	api := new(testAPI)
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+	api.url = srv.URL
+
+	var (
+		packed = filepath.Join(dir, "packed")
+		f      = &fetcher{client: srv.Client()}
+		want   []string
+	)
+	fetch := func(sigs ...string) {
+		t.Helper()
+		api.add(sigs...)
+		if err := f.fetch(srv.URL+"/", packed); err != nil {
+			t.Fatal(err)
+		}
+		for _, sig := range sigs {
+			want = append(want, fmt.Sprintf("%08x:%s", len(want), sig))
+		}
+		data, _ := ioutil.ReadFile(packed)
+		if have := strings.Join(want, "\n") + "\n"; string(data) != have {
+			t.Fatalf("packed file mismatch:\nhave:\n%s\nwant:\n%s", data, have)
+		}
+	}
+	// Initial fetch ends on a partially filled last page
+	fetch("a()", "b()", "c()")
+	if cursor, _ := ioutil.ReadFile(packed + ".cursor"); string(cursor) != srv.URL+"/?page=2 1\n" {
+		t.Errorf("have cursor %q", cursor)
+	}
+	// Nothing new, nothing to append
+	fetch()
+	// New signatures fill the last page, and spill onto a new one
+	fetch("d()", "e()")
+	fetch("f()")
+}
+
+func TestParseCursor(t *testing.T) {
+	tests := []struct {
+		cursor string
+		url    string
+		skip   int
+		fail   bool
+	}{
+		{cursor: "http://x/?page=2\n", url: "http://x/?page=2"},
+		{cursor: "http://x/?page=2 7\n", url: "http://x/?page=2", skip: 7},
+		{cursor: "http://x/?page=2 x\n", fail: true},
+		{cursor: "http://x/?page=2 -1\n", fail: true},
+		{cursor: "", fail: true},
+	}
+	for _, tt := range tests {
+		url, skip, err := parseCursor(tt.cursor)
+		if (err != nil) != tt.fail {
+			t.Errorf("%q: have error %v, want failure %v", tt.cursor, err, tt.fail)
+			continue
+		}
+		if url != tt.url || skip != tt.skip {
+			t.Errorf("%q: have %q %d, want %q %d", tt.cursor, url, skip, tt.url, tt.skip)
+		}
+	}
+}
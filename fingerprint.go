@@ -0,0 +1,363 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// knownInterfaces are the well-known ABIs contracts are fingerprinted against.
+// More can be added from file with -known.
+var knownInterfaces = map[string][]string{
+	"ERC20": {
+		"totalSupply()", "balanceOf(address)", "transfer(address,uint256)",
+		"transferFrom(address,address,uint256)", "approve(address,uint256)",
+		"allowance(address,address)",
+	},
+	"ERC20Metadata": {
+		"name()", "symbol()", "decimals()",
+	},
+	"ERC721": {
+		"balanceOf(address)", "ownerOf(uint256)", "safeTransferFrom(address,address,uint256,bytes)",
+		"safeTransferFrom(address,address,uint256)", "transferFrom(address,address,uint256)",
+		"approve(address,uint256)", "setApprovalForAll(address,bool)", "getApproved(uint256)",
+		"isApprovedForAll(address,address)",
+	},
+	"ERC721Metadata": {
+		"name()", "symbol()", "tokenURI(uint256)",
+	},
+	"ERC1155": {
+		"balanceOf(address,uint256)", "balanceOfBatch(address[],uint256[])",
+		"setApprovalForAll(address,bool)", "isApprovedForAll(address,address)",
+		"safeTransferFrom(address,address,uint256,uint256,bytes)",
+		"safeBatchTransferFrom(address,address,uint256[],uint256[],bytes)",
+	},
+	"ERC165": {
+		"supportsInterface(bytes4)",
+	},
+	"ERC4626": {
+		"asset()", "totalAssets()", "convertToShares(uint256)", "convertToAssets(uint256)",
+		"maxDeposit(address)", "previewDeposit(uint256)", "deposit(uint256,address)",
+		"maxMint(address)", "previewMint(uint256)", "mint(uint256,address)",
+		"maxWithdraw(address)", "previewWithdraw(uint256)", "withdraw(uint256,address,address)",
+		"maxRedeem(address)", "previewRedeem(uint256)", "redeem(uint256,address,address)",
+	},
+	"Ownable": {
+		"owner()", "renounceOwnership()", "transferOwnership(address)",
+	},
+	"Pausable": {
+		"paused()",
+	},
+	"AccessControl": {
+		"hasRole(bytes32,address)", "getRoleAdmin(bytes32)", "grantRole(bytes32,address)",
+		"revokeRole(bytes32,address)", "renounceRole(bytes32,address)",
+	},
+	"UUPSUpgradeable": {
+		"proxiableUUID()", "upgradeTo(address)", "upgradeToAndCall(address,bytes)",
+	},
+	"WETH9": {
+		"deposit()", "withdraw(uint256)",
+	},
+}
+
+// fingerprintMatch is the similarity between a contract and a known interface.
+type fingerprintMatch struct {
+	Name    string // name of the known interface
+	Found   int    // number of interface selectors present in the contract
+	Total   int    // number of selectors in the interface
+	Exact   bool   // whether the selector sets are identical
+	Missing []string
+}
+
+// scanSelectors extracts the function selectors from the dispatcher of some
+// deployed bytecode. Solidity and vyper both compare the calldata selector
+// against PUSH4 constants, so every PUSH4 which is directly (or via a DUP2)
+// consumed by an EQ is considered a selector. Since the shortest push is used
+// for the constant, selectors with a leading zero byte are pushed by a PUSH3,
+// and left-padded again here. Shorter pushes are ignored, they are mostly
+// plain small constants. Push data is skipped, so that constants embedded in
+// other push instructions are not misinterpreted.
+func scanSelectors(code []byte) []string {
+	set := make(map[string]struct{})
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if op < vm.PUSH1 || op > vm.PUSH32 {
+			continue
+		}
+		size := int(op-vm.PUSH1) + 1
+		if (op == vm.PUSH3 || op == vm.PUSH4) && pc+size+1 < len(code) {
+			next := vm.OpCode(code[pc+size+1])
+			if next == vm.DUP2 && pc+size+2 < len(code) {
+				next = vm.OpCode(code[pc+size+2])
+			}
+			if next == vm.EQ {
+				sel := make([]byte, 4)
+				copy(sel[4-size:], code[pc+1:pc+1+size])
+				set[hex.EncodeToString(sel)] = struct{}{}
+			}
+		}
+		pc += size
+	}
+	selectors := make([]string, 0, len(set))
+	for sel := range set {
+		selectors = append(selectors, sel)
+	}
+	sort.Strings(selectors)
+	return selectors
+}
+
+// fingerprint returns the hex-encoded keccak256 hash of the sorted selector
+// set, which is identical for all contracts exposing the same methods.
+func fingerprint(selectors []string) string {
+	sorted := append([]string{}, selectors...)
+	sort.Strings(sorted)
+	return fmt.Sprintf("%x", crypto.Keccak256([]byte(strings.Join(sorted, ","))))
+}
+
+// interfaceSelectors returns the sorted selectors of the given signatures.
+func interfaceSelectors(sigs []string) []string {
+	selectors := make([]string, len(sigs))
+	for i, sig := range sigs {
+		selectors[i] = fmt.Sprintf("%x", crypto.Keccak256([]byte(sig))[:4])
+	}
+	sort.Strings(selectors)
+	return selectors
+}
+
+// matchInterfaces compares the contract selectors against the known interfaces
+// and returns all interfaces with at least one selector present, best first.
+func matchInterfaces(selectors []string, known map[string][]string) []fingerprintMatch {
+	have := make(map[string]bool, len(selectors))
+	for _, sel := range selectors {
+		have[sel] = true
+	}
+	contractPrint := fingerprint(selectors)
+
+	var matches []fingerprintMatch
+	for name, sigs := range known {
+		m := fingerprintMatch{Name: name, Total: len(sigs)}
+		for _, sig := range sigs {
+			if have[fmt.Sprintf("%x", crypto.Keccak256([]byte(sig))[:4])] {
+				m.Found++
+			} else {
+				m.Missing = append(m.Missing, sig)
+			}
+		}
+		if m.Found == 0 {
+			continue
+		}
+		m.Exact = fingerprint(interfaceSelectors(sigs)) == contractPrint
+		matches = append(matches, m)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		ri := float64(matches[i].Found) / float64(matches[i].Total)
+		rj := float64(matches[j].Found) / float64(matches[j].Total)
+		if ri != rj {
+			return ri > rj
+		}
+		if matches[i].Total != matches[j].Total {
+			return matches[i].Total > matches[j].Total
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches
+}
+
+// delegationPrefix marks the code of an EIP-7702 delegated account, which is
+// followed by the address of the contract whose code the account executes.
+var delegationPrefix = []byte{0xef, 0x01, 0x00}
+
+// delegationTarget returns the address an EIP-7702 delegation designator
+// points to, if the code is one.
+func delegationTarget(code []byte) (common.Address, bool) {
+	if len(code) != len(delegationPrefix)+common.AddressLength || !bytes.HasPrefix(code, delegationPrefix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(delegationPrefix):]), true
+}
+
+// fetchCode retrieves the code of an account from a json-rpc endpoint.
+func fetchCode(client *http.Client, url string, addr common.Address) ([]byte, error) {
+	req, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_getCode",
+		"params":  []interface{}{addr, "latest"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Post(url, "application/json", bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http status %v", res.Status)
+	}
+	var reply struct {
+		Result hexutil.Bytes `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&reply); err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, fmt.Errorf("eth_getCode: %v", reply.Error.Message)
+	}
+	return reply.Result, nil
+}
+
+// delegateCode returns the code of the delegate of an EIP-7702 delegated
+// account, fetched from the json-rpc endpoint. Delegations are not chained,
+// so a delegate which is delegated itself has no code to run.
+func delegateCode(client *http.Client, url string, target common.Address) ([]byte, error) {
+	code, err := fetchCode(client, url, target)
+	if err != nil {
+		return nil, fmt.Errorf("fetching delegate %v: %v", target.Hex(), err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("delegate %v has no code", target.Hex())
+	}
+	if _, ok := delegationTarget(code); ok {
+		return nil, fmt.Errorf("delegate %v is a delegated account itself", target.Hex())
+	}
+	return code, nil
+}
+
+// runFingerprint implements the fingerprint command, which scans hex-encoded
+// bytecode, and prints its selectors (resolved against the optional database)
+// and the known interfaces it resembles. The code of an EIP-7702 delegated
+// account has no selectors of its own, the code of its delegate is fetched
+// from the rpc endpoint instead, or only the delegate reported without one.
+func runFingerprint(args []string) error {
+	fs := flag.NewFlagSet("fingerprint", flag.ExitOnError)
+	codeFile := fs.String("code", "", "file holding the hex bytecode to fingerprint")
+	dbFile := fs.String("db", "", "previously built database to resolve selectors against (optional)")
+	knownFile := fs.String("known", "", "json file of extra interfaces to fingerprint against (optional)")
+	rpcURL := fs.String("rpc", "", "json-rpc endpoint to fetch the code of EIP-7702 delegates from (optional)")
+	fs.Parse(args)
+	if *codeFile == "" {
+		return fmt.Errorf("bytecode not given")
+	}
+	data, err := ioutil.ReadFile(*codeFile)
+	if err != nil {
+		return err
+	}
+	code, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+	if err != nil {
+		return fmt.Errorf("invalid bytecode: %v", err)
+	}
+	if target, ok := delegationTarget(code); ok {
+		if *rpcURL == "" {
+			fmt.Printf("EIP-7702 delegation to %v, use -rpc to fingerprint the code of the delegate\n", target.Hex())
+			return nil
+		}
+		fmt.Printf("EIP-7702 delegation to %v\n", target.Hex())
+		if code, err = delegateCode(&http.Client{Timeout: time.Minute}, *rpcURL, target); err != nil {
+			return err
+		}
+	}
+	known := knownInterfaces
+	if *knownFile != "" {
+		if known, err = loadKnownInterfaces(*knownFile); err != nil {
+			return err
+		}
+	}
+	db := abidb.NewTable(4)
+	if *dbFile != "" {
+		if db, err = loadDatabase(*dbFile); err != nil {
+			return err
+		}
+	}
+	selectors := scanSelectors(code)
+	fmt.Printf("Found %d selectors, fingerprint %v\n", len(selectors), fingerprint(selectors))
+	for _, sel := range selectors {
+		if e, ok := db.Get(sel); ok {
+			fmt.Printf(" - %v %v\n", sel, e.Signature)
+		} else {
+			fmt.Printf(" - %v\n", sel)
+		}
+	}
+	for _, m := range matchInterfaces(selectors, known) {
+		switch {
+		case m.Exact:
+			fmt.Printf("%v: exact match\n", m.Name)
+		case m.Found == m.Total:
+			fmt.Printf("%v: all %d methods implemented\n", m.Name, m.Total)
+		default:
+			fmt.Printf("%v: %d/%d methods, missing %v\n", m.Name, m.Found, m.Total, strings.Join(m.Missing, ", "))
+		}
+	}
+	return nil
+}
+
+// loadKnownInterfaces reads a json object mapping interface names to lists of
+// signatures, and merges it with the builtin interfaces.
+func loadKnownInterfaces(file string) (map[string][]string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var extra map[string][]string
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, err
+	}
+	known := make(map[string][]string, len(knownInterfaces)+len(extra))
+	for name, sigs := range knownInterfaces {
+		known[name] = sigs
+	}
+	for name, sigs := range extra {
+		known[name] = sigs
+	}
+	return known, nil
+}
+
+// loadDatabase reads a previously built selector database, in either the plain
+// or the extended json format, the binary or the sqlite one.
+func loadDatabase(file string) (*abidb.Table, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if head, _ := r.Peek(len(sqliteMagic)); isSQLite(head) {
+		return readSQLite(file)
+	}
+	return abidb.ReadTable(r)
+}
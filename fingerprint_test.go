@@ -0,0 +1,180 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// dispatcher assembles a solc-style dispatcher comparing against the given
+// selectors, each pushed with the shortest push instruction.
+func dispatcher(sigs []string) []byte {
+	code := []byte{byte(vm.PUSH1), 0xe0, byte(vm.SHR)}
+	for _, sig := range sigs {
+		sel := crypto.Keccak256([]byte(sig))[:4]
+		for len(sel) > 1 && sel[0] == 0 {
+			sel = sel[1:]
+		}
+		code = append(code, byte(vm.DUP1), byte(vm.PUSH1)+byte(len(sel)-1))
+		code = append(code, sel...)
+		code = append(code, byte(vm.EQ), byte(vm.PUSH2), 0x01, 0x00, byte(vm.JUMPI))
+	}
+	return code
+}
+
+func TestScanSelectors(t *testing.T) {
+	tests := []struct {
+		name string
+		code []byte
+		want []string
+	}{
+		{"push4", []byte{byte(vm.DUP1), byte(vm.PUSH4), 0xa9, 0x05, 0x9c, 0xbb, byte(vm.EQ)}, []string{"a9059cbb"}},
+		{"push4 dup2", []byte{byte(vm.PUSH4), 0xa9, 0x05, 0x9c, 0xbb, byte(vm.DUP2), byte(vm.EQ)}, []string{"a9059cbb"}},
+		{"push3 leading zero", []byte{byte(vm.DUP1), byte(vm.PUSH3), 0xfd, 0xd5, 0x8e, byte(vm.EQ)}, []string{"00fdd58e"}},
+		{"push1 ignored", []byte{byte(vm.DUP1), byte(vm.PUSH1), 0x01, byte(vm.EQ)}, []string{}},
+		{"push4 not compared", []byte{byte(vm.PUSH4), 0xa9, 0x05, 0x9c, 0xbb, byte(vm.ADD)}, []string{}},
+		{"push data skipped", []byte{byte(vm.PUSH6), byte(vm.PUSH4), 0xa9, 0x05, 0x9c, 0xbb, byte(vm.EQ)}, []string{}},
+		{"truncated", []byte{byte(vm.DUP1), byte(vm.PUSH4), 0xa9, 0x05}, []string{}},
+	}
+	for _, tt := range tests {
+		if have := scanSelectors(tt.code); !reflect.DeepEqual(have, tt.want) {
+			t.Errorf("%s: have %v, want %v", tt.name, have, tt.want)
+		}
+	}
+}
+
+func TestFingerprintLeadingZeroSelector(t *testing.T) {
+	// ERC1155 balanceOf(address,uint256) is 0x00fdd58e, pushed with a PUSH3
+	sigs := knownInterfaces["ERC1155"]
+	selectors := scanSelectors(dispatcher(sigs))
+	if want := interfaceSelectors(sigs); !reflect.DeepEqual(selectors, want) {
+		t.Fatalf("selectors mismatch: have %v, want %v", selectors, want)
+	}
+	matches := matchInterfaces(selectors, knownInterfaces)
+	if len(matches) == 0 || matches[0].Name != "ERC1155" || !matches[0].Exact {
+		t.Fatalf("ERC1155 not matched exactly: %+v", matches)
+	}
+}
+
+func TestDelegationTarget(t *testing.T) {
+	target := common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b")
+	code := append([]byte{0xef, 0x01, 0x00}, target.Bytes()...)
+	if have, ok := delegationTarget(code); !ok || have != target {
+		t.Errorf("have %v (%v), want %v", have.Hex(), ok, target.Hex())
+	}
+	for _, code := range [][]byte{code[:22], append(code, 0), append([]byte{0xef, 0x01, 0x01}, target.Bytes()...), dispatcher([]string{"name()"})} {
+		if _, ok := delegationTarget(code); ok {
+			t.Errorf("%x: reported as delegation", code)
+		}
+	}
+}
+
+// testNode answers eth_getCode requests from the given accounts.
+func testNode(t *testing.T, accounts map[common.Address][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "eth_getCode" || len(req.Params) != 2 {
+			t.Errorf("unexpected request %+v, %v", req, err)
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"no such method"}}`)
+			return
+		}
+		var addr common.Address
+		json.Unmarshal(req.Params[0], &addr)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%x"}`, accounts[addr])
+	}))
+}
+
+func TestDelegateCode(t *testing.T) {
+	var (
+		delegate  = common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b")
+		delegated = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		empty     = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		code      = dispatcher(knownInterfaces["ERC20"])
+	)
+	node := testNode(t, map[common.Address][]byte{
+		delegate:  code,
+		delegated: append([]byte{0xef, 0x01, 0x00}, delegate.Bytes()...),
+	})
+	defer node.Close()
+
+	have, err := delegateCode(node.Client(), node.URL, delegate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := interfaceSelectors(knownInterfaces["ERC20"]); !reflect.DeepEqual(scanSelectors(have), want) {
+		t.Errorf("have selectors %v, want %v", scanSelectors(have), want)
+	}
+	for addr, want := range map[common.Address]string{delegated: "delegated account itself", empty: "has no code"} {
+		if _, err := delegateCode(node.Client(), node.URL, addr); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%v: have error %v, want %q", addr.Hex(), err, want)
+		}
+	}
+}
+
+func TestRunFingerprint(t *testing.T) {
+	dir, err := ioutil.TempDir("", "abidb-fingerprint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	delegate := common.HexToAddress("0x63c0c19a282a1b52b07dd5a65b58948a07dae32b")
+	node := testNode(t, map[common.Address][]byte{delegate: dispatcher(knownInterfaces["ERC20"])})
+	defer node.Close()
+	writeFiles(t, dir, map[string]string{
+		"code.hex":      fmt.Sprintf("0x%x\n", dispatcher(knownInterfaces["ERC721"])),
+		"delegated.hex": fmt.Sprintf("0xef0100%x", delegate.Bytes()),
+		"invalid.hex":   "0xzz",
+	})
+	code := func(name string) string { return filepath.Join(dir, name) }
+	tests := []struct {
+		args []string
+		ok   bool
+	}{
+		{[]string{"-code", code("code.hex")}, true},
+		{[]string{"-code", code("delegated.hex")}, true},
+		{[]string{"-code", code("delegated.hex"), "-rpc", node.URL}, true},
+		{[]string{"-code", code("invalid.hex")}, false},
+		{[]string{"-code", code("missing.hex")}, false},
+		{[]string{"-db", code("missing.json")}, false},
+	}
+	for _, tt := range tests {
+		err := runFingerprint(tt.args)
+		if tt.ok && err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.args, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%v: succeeded", tt.args)
+		}
+	}
+}
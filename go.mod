@@ -4,5 +4,7 @@ go 1.15
 
 require (
 	github.com/ethereum/go-ethereum v1.10.3
-	github.com/iancoleman/orderedmap v0.2.0
+	github.com/fsnotify/fsnotify v1.4.9
+	github.com/mattn/go-sqlite3 v1.14.16
+	gopkg.in/yaml.v2 v2.4.0
 )
@@ -0,0 +1,259 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// inputFormats lists the supported input layouts. With auto, directories are
+// read as 4bytes checkouts, .csv files as openchain exports, and all other
+// files as packed files.
+var inputFormats = []string{"auto", "4bytes", "packed", "openchain"}
+
+// input is a single signature source.
+type input struct {
+	path   string
+	format string
+}
+
+// parseInputs pairs the comma-separated input paths with their formats. A
+// single format applies to all inputs, otherwise one is needed per input.
+func parseInputs(paths, formats string) ([]input, error) {
+	var (
+		names = strings.Split(paths, ",")
+		kinds = strings.Split(formats, ",")
+	)
+	if len(kinds) != 1 && len(kinds) != len(names) {
+		return nil, fmt.Errorf("have %d inputs but %d input formats", len(names), len(kinds))
+	}
+	inputs := make([]input, len(names))
+	for i, name := range names {
+		kind := strings.TrimSpace(kinds[0])
+		if len(kinds) > 1 {
+			kind = strings.TrimSpace(kinds[i])
+		}
+		if !containsString(inputFormats, kind) {
+			return nil, fmt.Errorf("unknown input format %q (available: %v)", kind, strings.Join(inputFormats, ", "))
+		}
+		inputs[i] = input{path: strings.TrimSpace(name), format: kind}
+	}
+	return inputs, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// readInput reads the signatures from the given input, and passes them to
+// commit once validated.
+func readInput(in input, b *abidb.Builder, commit func(*record)) error {
+	format := in.format
+	if format == "auto" {
+		info, err := os.Stat(in.path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			format = "4bytes"
+		case strings.EqualFold(filepath.Ext(in.path), ".csv"):
+			format = "openchain"
+		default:
+			format = "packed"
+		}
+	}
+	tagged := func(r *record) {
+		r.input = in.path
+		commit(r)
+	}
+	switch format {
+	case "4bytes":
+		return readFiles(in.path, b, tagged)
+	case "openchain":
+		return readLines(in.path, "openchain", parseOpenchainLine, b, tagged)
+	default:
+		return readLines(in.path, "packed", parsePackedLine, b, tagged)
+	}
+}
+
+// lineParser splits a line of a line-based input into the claimed key, and
+// the signatures. Lines without key nor error are skipped.
+type lineParser func(line int, text string) ([]byte, string, error)
+
+// readLines reads all signatures from a line-based input file, and passes them
+// to commit once validated. Such files may list a key several times, the
+// signatures of all its lines are then resolved by the collision policy, the
+// same as ;-separated ones on a single line.
+func readLines(file string, kind string, parse lineParser, b *abidb.Builder, commit func(*record)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return processRecords(b, info.Size(), func(emit func(*record)) error {
+		seen := make(map[string]bool)
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), maxPackedLine)
+		for line := 1; scanner.Scan(); line++ {
+			size := int64(len(scanner.Bytes()) + 1)
+			source := fmt.Sprintf("%v:%d", file, line)
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				emit(&record{skip: true, size: size})
+				continue
+			}
+			sig, data, err := parse(line, text)
+			if err != nil {
+				emit(&record{source: source, msg: "Invalid " + kind + " entry", ctx: []interface{}{"err", err}, kind: "malformed", size: size})
+				continue
+			}
+			if sig == nil {
+				emit(&record{skip: true, size: size})
+				continue
+			}
+			if b.Database().Table(sig) == nil {
+				emit(&record{key: sig, source: source, msg: "Invalid sig, wrong length", kind: "wrong-length", size: size})
+				continue
+			}
+			key := string(sig)
+			emit(&record{key: sig, data: data, source: source, keepFirst: true, repeated: seen[key], size: size})
+			seen[key] = true
+		}
+		return scanner.Err()
+	}, commit)
+}
+
+// parseOpenchainLine parses a line of an openchain.xyz signature export, in the
+// form selector,signature. The signature may be quoted csv style, as it
+// contains commas itself, and a header line is skipped.
+func parseOpenchainLine(line int, text string) ([]byte, string, error) {
+	kv := strings.SplitN(text, ",", 2)
+	if len(kv) != 2 {
+		return nil, "", fmt.Errorf("missing separator")
+	}
+	sig, err := abidb.ParseKey(strings.Trim(kv[0], `"`))
+	if err != nil {
+		if line == 1 {
+			return nil, "", nil // header
+		}
+		return nil, "", err
+	}
+	data := strings.TrimSpace(kv[1])
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = strings.ReplaceAll(data[1:len(data)-1], `""`, `"`)
+	}
+	return sig, data, nil
+}
+
+// merge tracks the origin of entries when reading several inputs, or is nil
+// for a single one.
+var merge *crossMerge
+
+// crossMerge records which input each entry was taken from, to report the
+// entries the inputs agree on, and the ones they conflict on.
+type crossMerge struct {
+	origins    map[string]string // key to input of kept entry
+	duplicates int
+	conflicts  []conflict
+}
+
+// conflict is a key for which two inputs claim different valid signatures.
+type conflict struct {
+	Selector string         `json:"selector"`
+	Kept     conflictSource `json:"kept"`
+	Skipped  conflictSource `json:"skipped"`
+}
+
+// conflictSource is the signature claimed by one of the inputs.
+type conflictSource struct {
+	Input     string `json:"input"`
+	Signature string `json:"signature"`
+}
+
+func newCrossMerge() *crossMerge {
+	return &crossMerge{origins: make(map[string]string), conflicts: []conflict{}}
+}
+
+// added records the input an entry was taken from.
+func (m *crossMerge) added(key []byte, input string) {
+	m.origins[fmt.Sprintf("%x", key)] = input
+}
+
+// skipped records a valid entry of an input, which was not taken due to an
+// existing one. It is a duplicate or a conflict if the existing entry came
+// from another input.
+func (m *crossMerge) skipped(key []byte, existing *abidb.Entry, input, signature string) {
+	origin, ok := m.origins[fmt.Sprintf("%x", key)]
+	if !ok || origin == input {
+		return
+	}
+	if canonicalSignature(existing.Signature) == canonicalSignature(signature) {
+		m.duplicates++
+		return
+	}
+	m.conflicts = append(m.conflicts, conflict{
+		Selector: fmt.Sprintf("%x", key),
+		Kept:     conflictSource{origin, existing.Signature},
+		Skipped:  conflictSource{input, signature},
+	})
+}
+
+// canonicalSignature drops the indexed markers of event signatures, which not
+// all sources retain.
+func canonicalSignature(sig string) string {
+	return strings.Replace(sig, " indexed", "", -1)
+}
+
+// writeReport saves the cross-source conflicts as a json list to the given file.
+func (m *crossMerge) writeReport(outfile string) error {
+	data, err := json.MarshalIndent(m.conflicts, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Saving conflict report", "conflicts", len(m.conflicts), "file", outfile)
+	return ioutil.WriteFile(outfile, data, 0644)
+}
+
+// writeConflictReport saves the conflicts between the inputs. A single input
+// has none, but the empty report is still written, so consumers need not care
+// about the number of inputs.
+func writeConflictReport(outfile string) error {
+	if merge == nil {
+		return newCrossMerge().writeReport(outfile)
+	}
+	return merge.writeReport(outfile)
+}
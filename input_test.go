@@ -0,0 +1,93 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+func TestEmptyConflictReport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conflicts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "report.json")
+	if err := newCrossMerge().writeReport(file); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(file); string(data) != "[]" {
+		t.Errorf("have %q, want []", data)
+	}
+	// A single input doesn't merge, but still gets the report
+	file = filepath.Join(dir, "single.json")
+	defer func(m *crossMerge) { merge = m }(merge)
+	merge = nil
+	if err := writeConflictReport(file); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(file); string(data) != "[]" {
+		t.Errorf("single input: have %q, want []", data)
+	}
+}
+
+// Tests that a key listed on several lines collides, the same as ;-separated
+// signatures on a single line.
+func TestRepeatedLines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repeated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"split.txt":  "a9059cbb:transfer(address,uint256)\na9059cbb:many_msg_babbage(bytes1)\na9059cbb:transfer(address,uint256)\n70a08231:balanceOf(address)\n",
+		"joined.txt": "a9059cbb:transfer(address,uint256);many_msg_babbage(bytes1)\n70a08231:balanceOf(address)\n",
+	})
+	defer func(p *collisionPolicy) { collisions = p }(collisions)
+
+	build := func(name, policy string) (*abidb.Table, []collision) {
+		if collisions, err = newCollisionPolicy(policy, ""); err != nil {
+			t.Fatal(err)
+		}
+		b := abidb.NewBuilder()
+		in := input{path: filepath.Join(dir, name), format: "packed"}
+		if err := readInput(in, b, func(r *record) { r.commit(b) }); err != nil {
+			t.Fatal(err)
+		}
+		return b.Database().Methods, collisions.report
+	}
+	for _, policy := range []string{"first", "all"} {
+		joined, joinedReport := build("joined.txt", policy)
+		split, splitReport := build("split.txt", policy)
+		for _, key := range []string{"a9059cbb", "70a08231"} {
+			want, _ := joined.Get(key)
+			have, _ := split.Get(key)
+			if !reflect.DeepEqual(have.Signatures(), want.Signatures()) {
+				t.Errorf("%s %s: have %v, want %v", policy, key, have.Signatures(), want.Signatures())
+			}
+		}
+		if !reflect.DeepEqual(splitReport, joinedReport) {
+			t.Errorf("%s: report mismatch:\nhave %+v\nwant %+v", policy, splitReport, joinedReport)
+		}
+	}
+}
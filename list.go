@@ -0,0 +1,169 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// importList reads a hand-maintained signature list, and adds all valid
+// entries to the db. Curated entries take precedence over the ones already
+// present. The format is line based:
+//
+//	# Everything after a '#' is a comment.
+//	transfer(address,uint256)
+//	a9059cbb transfer(address,uint256) trust=high
+//	0x23b872dd transferFrom(address,address,uint256) note="allowance spend"
+//
+// The selector is optional, and computed from the signature if missing. The
+// supported annotations are trust and note, values containing spaces must be
+// double-quoted.
+func importList(file string, b *abidb.Builder) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var added, replaced int
+	for i, line := range strings.Split(string(data), "\n") {
+		sig, e, err := parseListLine(line)
+		if err != nil {
+			log.Warn("Invalid list entry", "source", fmt.Sprintf("%v:%d", file, i+1), "err", err)
+			report.reject("malformed")
+			continue
+		}
+		if e == nil {
+			continue
+		}
+		e.Source = fmt.Sprintf("%v:%d", file, i+1)
+		key := fmt.Sprintf("%x", sig)
+		old, exists := b.Database().Methods.Get(key)
+		if err := b.AddMethod(sig, *e); err != nil {
+			log.Warn("Rejected signature", "source", fmt.Sprintf("%v:%d", file, i+1), "err", err)
+			report.reject(rejectReason(err))
+			continue
+		}
+		if exists {
+			if prev := old.Signature; prev != e.Signature {
+				log.Info("Replacing signature from list", "sig", key, "have", prev, "list", e.Signature)
+			}
+			replaced++
+		} else {
+			added++
+		}
+	}
+	log.Info("Imported signature list", "file", file, "added", added, "replaced", replaced)
+	return nil
+}
+
+// parseListLine parses a single signature list line. Empty and comment-only
+// lines yield a nil entry.
+func parseListLine(line string) ([]byte, *abidb.Entry, error) {
+	fields, err := splitListLine(line)
+	if err != nil || len(fields) == 0 {
+		return nil, nil, err
+	}
+	var sig []byte
+	if !strings.Contains(fields[0], "(") {
+		if sig, err = abidb.ParseKey(fields[0]); err != nil || len(sig) != 4 {
+			return nil, nil, fmt.Errorf("invalid selector %q", fields[0])
+		}
+		fields = fields[1:]
+		if len(fields) == 0 {
+			return nil, nil, fmt.Errorf("signature missing")
+		}
+	}
+	e := &abidb.Entry{Signature: fields[0]}
+	if sig == nil {
+		sig = crypto.Keccak256([]byte(e.Signature))[:4]
+	}
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, nil, fmt.Errorf("invalid annotation %q", field)
+		}
+		switch kv[0] {
+		case "trust":
+			e.Trust = kv[1]
+		case "note":
+			e.Note = kv[1]
+		default:
+			return nil, nil, fmt.Errorf("unknown annotation %q", kv[0])
+		}
+	}
+	return sig, e, nil
+}
+
+// splitListLine splits a line into whitespace separated fields, stopping at
+// the first '#' outside of a quoted value. Quoted values are unquoted.
+func splitListLine(line string) ([]string, error) {
+	var (
+		fields []string
+		field  strings.Builder
+		quoted bool
+		start  int
+	)
+	flush := func() error {
+		if field.Len() == 0 {
+			return nil
+		}
+		f := field.String()
+		field.Reset()
+		if eq := strings.Index(f, "=\""); eq >= 0 {
+			val, err := strconv.Unquote(f[eq+1:])
+			if err != nil {
+				return fmt.Errorf("invalid quoting in %q", f)
+			}
+			f = f[:eq+1] + val
+		}
+		fields = append(fields, f)
+		return nil
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quoted && c == '\\' && i+1 < len(line):
+			field.WriteByte(c)
+			field.WriteByte(line[i+1])
+			i++
+			continue
+		case c == '"':
+			if !quoted {
+				start = i
+			}
+			quoted = !quoted
+		case !quoted && c == '#':
+			return fields, flush()
+		case !quoted && (c == ' ' || c == '\t' || c == '\r'):
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		field.WriteByte(c)
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote at column %d", start+1)
+	}
+	return fields, flush()
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+func TestSplitListLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		fields []string
+		fail   bool
+	}{
+		{line: "", fields: nil},
+		{line: "   \t", fields: nil},
+		{line: "# only a comment", fields: nil},
+		{line: "transfer(address,uint256)", fields: []string{"transfer(address,uint256)"}},
+		{line: " a9059cbb\ttransfer(address,uint256)  trust=high\r", fields: []string{"a9059cbb", "transfer(address,uint256)", "trust=high"}},
+		{line: "transfer(address,uint256) # trailing comment", fields: []string{"transfer(address,uint256)"}},
+		{line: "transfer(address,uint256)# no space", fields: []string{"transfer(address,uint256)"}},
+		{line: `name() note="with spaces"`, fields: []string{"name()", "note=with spaces"}},
+		{line: `name() note="not # a comment" # but this is`, fields: []string{"name()", "note=not # a comment"}},
+		{line: `name() note="say \"hi\""`, fields: []string{"name()", `note=say "hi"`}},
+		{line: `name() note="back\\slash"`, fields: []string{"name()", `note=back\slash`}},
+		{line: `name() note=""`, fields: []string{"name()", "note="}},
+		{line: `name() note="unterminated`, fail: true},
+		{line: `name() note="escaped end\"`, fail: true},
+		{line: `name() note="trailing\`, fail: true},
+		{line: `name() note="a"b`, fail: true},
+		{line: `name() note="bad \q escape"`, fail: true},
+	}
+	for _, tt := range tests {
+		fields, err := splitListLine(tt.line)
+		if (err != nil) != tt.fail {
+			t.Errorf("%q: have error %v, want failure %v", tt.line, err, tt.fail)
+			continue
+		}
+		if !tt.fail && !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%q: have %q, want %q", tt.line, fields, tt.fields)
+		}
+	}
+}
+
+func TestParseListLine(t *testing.T) {
+	tests := []struct {
+		line  string
+		key   string
+		entry *abidb.Entry
+		fail  bool
+	}{
+		{line: "# comment"},
+		{line: "transfer(address,uint256)", key: "a9059cbb", entry: &abidb.Entry{Signature: "transfer(address,uint256)"}},
+		{line: "a9059cbb transfer(address,uint256) trust=high", key: "a9059cbb", entry: &abidb.Entry{Signature: "transfer(address,uint256)", Trust: "high"}},
+		{
+			line:  `0x23b872dd transferFrom(address,address,uint256) note="allowance spend" trust=verified # curated`,
+			key:   "23b872dd",
+			entry: &abidb.Entry{Signature: "transferFrom(address,address,uint256)", Trust: "verified", Note: "allowance spend"},
+		},
+		// The claimed selector is kept, checking it is left to the builder
+		{line: "deadbeef transfer(address,uint256)", key: "deadbeef", entry: &abidb.Entry{Signature: "transfer(address,uint256)"}},
+		{line: "a9059cbb", fail: true},
+		{line: "abcd transfer(address,uint256)", fail: true},
+		{line: "xyz transfer(address,uint256)", fail: true},
+		{line: "transfer(address,uint256) high", fail: true},
+		{line: "transfer(address,uint256) owner=me", fail: true},
+		{line: `transfer(address,uint256) note="open`, fail: true},
+	}
+	for _, tt := range tests {
+		key, entry, err := parseListLine(tt.line)
+		if (err != nil) != tt.fail {
+			t.Errorf("%q: have error %v, want failure %v", tt.line, err, tt.fail)
+			continue
+		}
+		if tt.fail {
+			continue
+		}
+		if have := fmt.Sprintf("%x", key); have != tt.key {
+			t.Errorf("%q: have key %s, want %s", tt.line, have, tt.key)
+		}
+		if !reflect.DeepEqual(entry, tt.entry) {
+			t.Errorf("%q: have entry %+v, want %+v", tt.line, entry, tt.entry)
+		}
+	}
+}
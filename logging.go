@@ -0,0 +1,137 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+func init() {
+	// Subcommands log at the default level, the build reconfigures by flags
+	log.Root().SetHandler(log.LvlFilterHandler(log.LvlInfo, log.StreamHandler(os.Stderr, log.TerminalFormat(false))))
+}
+
+// setupLogging configures the logger from the command line flags. Quiet only
+// logs errors, verbose includes the debug messages.
+func setupLogging(format string, quiet, verbose bool) error {
+	var fmtr log.Format
+	switch format {
+	case "text":
+		fmtr = log.TerminalFormat(false)
+	case "json":
+		fmtr = log.JSONFormat()
+	default:
+		return fmt.Errorf("unknown log format %q (available: text, json)", format)
+	}
+	lvl := log.LvlInfo
+	switch {
+	case quiet && verbose:
+		return fmt.Errorf("quiet and verbose are mutually exclusive")
+	case quiet:
+		lvl = log.LvlError
+	case verbose:
+		lvl = log.LvlDebug
+	}
+	log.Root().SetHandler(log.LvlFilterHandler(lvl, log.StreamHandler(os.Stderr, fmtr)))
+	return nil
+}
+
+// rejectReason categorizes a validation failure.
+func rejectReason(err error) string {
+	if _, ok := err.(*abidb.MismatchError); ok {
+		return "hash-mismatch"
+	}
+	return "unparsable"
+}
+
+// report collects the statistics of a build.
+var report = &buildReport{Rejected: make(map[string]int), start: time.Now()}
+
+// buildReport is the machine-readable summary of a build.
+type buildReport struct {
+	Inputs        []string       `json:"inputs"`
+	Methods       int            `json:"methods"`
+	Events        int            `json:"events"`
+	Rejected      map[string]int `json:"rejected"` // rejected entries by reason
+	Quarantined   int            `json:"quarantined"`
+	Canonicalized int            `json:"canonicalized"` // entries stored in canonical form
+	Collisions    int            `json:"collisions"`
+	Duplicates    int            `json:"duplicates"` // entries several inputs agree on
+	Conflicts     int            `json:"conflicts"`  // entries several inputs disagree on
+	Elapsed       float64        `json:"elapsed"`    // seconds
+
+	start time.Time
+}
+
+// reject counts a rejected entry.
+func (r *buildReport) reject(reason string) {
+	r.Rejected[reason]++
+}
+
+// rejected returns the total number of rejected entries.
+func (r *buildReport) rejected() int {
+	var n int
+	for _, count := range r.Rejected {
+		n += count
+	}
+	return n
+}
+
+// finish fills in the final counts, and logs the summary.
+func (r *buildReport) finish(inputs []input, b *abidb.Builder) {
+	r.Inputs = r.Inputs[:0]
+	for _, in := range inputs {
+		r.Inputs = append(r.Inputs, in.path)
+	}
+	r.Methods = b.Database().Methods.Len()
+	r.Events = b.Database().Events.Len()
+	r.Quarantined = len(b.Mismatches())
+	r.Canonicalized = b.Canonicalized()
+	r.Collisions = len(collisions.report)
+	if merge != nil {
+		r.Duplicates, r.Conflicts = merge.duplicates, len(merge.conflicts)
+	}
+	r.Elapsed = time.Since(r.start).Seconds()
+
+	var reasons []string
+	for reason, n := range r.Rejected {
+		reasons = append(reasons, fmt.Sprintf("%s=%d", reason, n))
+	}
+	sort.Strings(reasons)
+	log.Info("Build finished", "methods", r.Methods, "events", r.Events, "rejected", r.rejected(),
+		"reasons", strings.Join(reasons, ","), "canonicalized", r.Canonicalized, "collisions", r.Collisions,
+		"elapsed", time.Since(r.start).Round(time.Millisecond))
+}
+
+// write saves the report as json to the given file.
+func (r *buildReport) write(outfile string) error {
+	data, err := json.MarshalIndent(r, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Saving build report", "file", outfile)
+	return ioutil.WriteFile(outfile, data, 0644)
+}
@@ -0,0 +1,347 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// manifest describes the files of a build, so that consumers can check what
+// they embed. If signed, the signature of the manifest file is stored in a
+// .sig file next to it.
+type manifest struct {
+	Source    string         `json:"source,omitempty"` // commit or url the input was taken from
+	Timestamp string         `json:"timestamp"`        // build time, RFC 3339
+	Files     []manifestFile `json:"files"`
+}
+
+// manifestFile is a single output file listed in the manifest.
+type manifestFile struct {
+	File   string `json:"file"` // path relative to the manifest
+	SHA256 string `json:"sha256"`
+	Count  int    `json:"count"` // number of entries in the file
+}
+
+// manifestSignature is the content of the .sig file of a signed manifest. The
+// signature is an EIP-191 personal message signature of the manifest file, as
+// produced by clef or any other wallet.
+type manifestSignature struct {
+	Signer    common.Address `json:"signer"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// outputFiles lists the files written for the table in the given targets,
+// along with the number of entries in each.
+func outputFiles(db *abidb.Table, targets []target, out string) []manifestFile {
+	var files []manifestFile
+	if shardPrefix == 0 {
+		for _, t := range targets {
+			files = append(files, manifestFile{File: t.file, Count: db.Len()})
+		}
+		return files
+	}
+	counts := make(map[string]int)
+	for _, key := range db.Keys() {
+		counts[key[:shardPrefix]]++
+	}
+	files = append(files, manifestFile{File: filepath.Join(out, "index.json"), Count: db.Len()})
+	for i := 0; i < 1<<(4*uint(shardPrefix)); i++ {
+		prefix := fmt.Sprintf("%0*x", shardPrefix, i)
+		for _, t := range targets {
+			file := filepath.Join(out, prefix+encoders[t.format].ext)
+			files = append(files, manifestFile{File: file, Count: counts[prefix]})
+		}
+	}
+	return files
+}
+
+// writeManifest hashes the given files, and writes the manifest listing them.
+// If a key is given, the manifest is signed too.
+func writeManifest(outfile, source string, files []manifestFile, key *ecdsa.PrivateKey) error {
+	m := manifest{
+		Source:    source,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Files:     files,
+	}
+	base := filepath.Dir(outfile)
+	for i, f := range m.Files {
+		sum, err := hashFile(f.File)
+		if err != nil {
+			return err
+		}
+		m.Files[i].SHA256 = sum
+		if rel, err := filepath.Rel(base, f.File); err == nil {
+			m.Files[i].File = filepath.ToSlash(rel)
+		}
+	}
+	data, err := json.MarshalIndent(m, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Saving manifest", "files", len(m.Files), "file", outfile)
+	if err := ioutil.WriteFile(outfile, data, 0644); err != nil {
+		return err
+	}
+	if key == nil {
+		return nil
+	}
+	sig, err := crypto.Sign(accounts.TextHash(data), key)
+	if err != nil {
+		return err
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // personal_sign convention
+	signed, err := json.MarshalIndent(manifestSignature{crypto.PubkeyToAddress(key.PublicKey), sig}, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Signed manifest", "signer", crypto.PubkeyToAddress(key.PublicKey))
+	return ioutil.WriteFile(outfile+".sig", signed, 0644)
+}
+
+// hashFile returns the hex encoded SHA-256 of the file content.
+func hashFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// loadSigningKey reads the manifest signing key, either a hex encoded private
+// key file, or a keystore file with its password file. Without either, no key
+// is returned.
+func loadSigningKey(keyFile, keystoreFile, passwordFile string) (*ecdsa.PrivateKey, error) {
+	switch {
+	case keyFile != "" && keystoreFile != "":
+		return nil, fmt.Errorf("both private key and keystore given")
+	case keyFile != "":
+		return crypto.LoadECDSA(keyFile)
+	case keystoreFile != "":
+		data, err := ioutil.ReadFile(keystoreFile)
+		if err != nil {
+			return nil, err
+		}
+		var password string
+		if passwordFile != "" {
+			pw, err := ioutil.ReadFile(passwordFile)
+			if err != nil {
+				return nil, err
+			}
+			password = strings.TrimRight(string(pw), "\r\n")
+		}
+		key, err := keystore.DecryptKey(data, password)
+		if err != nil {
+			return nil, err
+		}
+		return key.PrivateKey, nil
+	}
+	return nil, nil
+}
+
+// gitSource returns the commit checked out in the git repository containing
+// path, which is the case for a checkout of the 4bytes repository. The parent
+// directories are searched, since the signatures are in a subdirectory of the
+// repository. Outside of a repository, an empty source is returned.
+func gitSource(path string) (string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		gitDir := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitDir); err == nil {
+			if !info.IsDir() {
+				// Worktrees and submodules point to the git directory instead
+				if gitDir, err = gitDirLink(gitDir); err != nil {
+					return "", err
+				}
+			}
+			commit, err := gitHead(gitDir)
+			if err != nil {
+				return "", err
+			}
+			return "git:" + commit, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// gitDirLink resolves a .git file of the form `gitdir: path`.
+func gitDirLink(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	link := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(link, "gitdir: ") {
+		return "", fmt.Errorf("invalid git link %v", file)
+	}
+	gitDir := filepath.FromSlash(strings.TrimPrefix(link, "gitdir: "))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(file), gitDir)
+	}
+	return gitDir, nil
+}
+
+// gitHead returns the commit HEAD of the git directory refers to, resolving
+// loose as well as packed refs.
+func gitHead(gitDir string) (string, error) {
+	head, err := ioutil.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	ref := strings.TrimSpace(string(head))
+	if !strings.HasPrefix(ref, "ref: ") {
+		return ref, nil // detached
+	}
+	ref = strings.TrimPrefix(ref, "ref: ")
+	// Worktrees keep their HEAD, but share the refs of the main repository
+	dirs := []string{gitDir}
+	if common, err := ioutil.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		dir := filepath.FromSlash(strings.TrimSpace(string(common)))
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(gitDir, dir)
+		}
+		dirs = append(dirs, dir)
+	}
+	for _, dir := range dirs {
+		if commit, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(commit)), nil
+		}
+		packed, err := ioutil.ReadFile(filepath.Join(dir, "packed-refs"))
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(packed), "\n") {
+			if fields := strings.Fields(line); len(fields) == 2 && fields[1] == ref {
+				return fields[0], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unresolvable git ref %v in %v", ref, gitDir)
+}
+
+// runVerify implements the verify command, checking the files listed in a
+// manifest against their hashes, and the signature of the manifest if any.
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	file := fs.String("manifest", "", "manifest to verify")
+	signer := fs.String("signer", "", "address the manifest must be signed by (without it, the signer is not checked)")
+	fs.Parse(args)
+	if *file == "" {
+		return fmt.Errorf("manifest not given")
+	}
+	if *signer != "" && !common.IsHexAddress(*signer) {
+		return fmt.Errorf("invalid signer address %q", *signer)
+	}
+	data, err := ioutil.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	fmt.Printf("Manifest of %d files, built %v from %v\n", len(m.Files), m.Timestamp, m.Source)
+	var failed int
+	for _, f := range m.Files {
+		sum, err := hashFile(filepath.Join(filepath.Dir(*file), filepath.FromSlash(f.File)))
+		switch {
+		case err != nil:
+			fmt.Printf(" - %v: %v\n", f.File, err)
+			failed++
+		case sum != f.SHA256:
+			fmt.Printf(" - %v: hash mismatch, have %v want %v\n", f.File, sum, f.SHA256)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed verification", failed, len(m.Files))
+	}
+	fmt.Printf("All %d files match\n", len(m.Files))
+
+	sigData, err := ioutil.ReadFile(*file + ".sig")
+	if os.IsNotExist(err) && *signer == "" {
+		fmt.Println("Manifest is not signed")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var sig manifestSignature
+	if err := json.Unmarshal(sigData, &sig); err != nil {
+		return err
+	}
+	if len(sig.Signature) != crypto.SignatureLength {
+		return fmt.Errorf("invalid signature length %d", len(sig.Signature))
+	}
+	rsv := common.CopyBytes(sig.Signature)
+	if rsv[crypto.RecoveryIDOffset] >= 27 {
+		rsv[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(data), rsv)
+	if err != nil {
+		return err
+	}
+	recovered := crypto.PubkeyToAddress(*pub)
+	if recovered != sig.Signer {
+		return fmt.Errorf("signature by %v, not the claimed %v", recovered.Hex(), sig.Signer.Hex())
+	}
+	if *signer == "" {
+		// A valid signature only shows the manifest was not altered after
+		// signing, anyone could have made it.
+		log.Warn("Manifest signer NOT checked, pass -signer to verify who signed it", "recovered", recovered.Hex())
+		fmt.Printf("Manifest signed by %v (unverified signer)\n", recovered.Hex())
+		return nil
+	}
+	if recovered != common.HexToAddress(*signer) {
+		return fmt.Errorf("signed by %v, not %v", recovered.Hex(), *signer)
+	}
+	fmt.Printf("Manifest signed by %v\n", recovered.Hex())
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const testCommit = "874bccb940214db81d27d84ecc820934ecf59a50"
+
+// writeFiles creates the given files, relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGitSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		input string
+		want  string
+	}{
+		{"loose ref", map[string]string{
+			".git/HEAD":              "ref: refs/heads/master\n",
+			".git/refs/heads/master": testCommit + "\n",
+		}, ".", "git:" + testCommit},
+		{"subdirectory", map[string]string{
+			".git/HEAD":              "ref: refs/heads/master\n",
+			".git/refs/heads/master": testCommit + "\n",
+			"signatures/a9059cbb":    "transfer(address,uint256)",
+		}, "signatures", "git:" + testCommit},
+		{"packed ref", map[string]string{
+			".git/HEAD":        "ref: refs/heads/master\n",
+			".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + testCommit + " refs/heads/master\n",
+			"packed.txt":       "a9059cbb:transfer(address,uint256)\n",
+		}, "packed.txt", "git:" + testCommit},
+		{"detached", map[string]string{
+			".git/HEAD": testCommit + "\n",
+		}, ".", "git:" + testCommit},
+		{"worktree", map[string]string{
+			"main/.git/packed-refs":            testCommit + " refs/heads/feature\n",
+			"main/.git/worktrees/wt/HEAD":      "ref: refs/heads/feature\n",
+			"main/.git/worktrees/wt/commondir": "../..\n",
+			"wt/.git":                          "gitdir: ../main/.git/worktrees/wt\n",
+			"wt/signatures/a9059cbb":           "transfer(address,uint256)",
+		}, "wt/signatures", "git:" + testCommit},
+	}
+	for _, tt := range tests {
+		dir, err := ioutil.TempDir("", "gitsource")
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeFiles(t, dir, tt.files)
+		have, err := gitSource(filepath.Join(dir, tt.input))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else if have != tt.want {
+			t.Errorf("%s: have %q, want %q", tt.name, have, tt.want)
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGitSourceUnresolvable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gitsource")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{".git/HEAD": "ref: refs/heads/missing\n"})
+	if _, err := gitSource(dir); err == nil {
+		t.Fatal("missing ref resolved")
+	}
+}
+
+func TestVerifySigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "abidb-verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{"abi.json": `{"a9059cbb":"transfer(address,uint256)"}`})
+
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	file := filepath.Join(dir, "manifest.json")
+	files := []manifestFile{{File: filepath.Join(dir, "abi.json"), Count: 1}}
+	if err := writeManifest(file, "test", files, key); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		signer string
+		ok     bool
+	}{
+		{"", true}, // accepted, but only with a warning
+		{crypto.PubkeyToAddress(key.PublicKey).Hex(), true},
+		{crypto.PubkeyToAddress(other.PublicKey).Hex(), false},
+		{"0xabcd", false},
+	}
+	for _, tt := range tests {
+		err := runVerify([]string{"-manifest", file, "-signer", tt.signer})
+		if tt.ok && err != nil {
+			t.Errorf("signer %q: unexpected error: %v", tt.signer, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("signer %q: verified", tt.signer)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// mmMethod is the method description MetaMask stores for a resolved selector
+// (the knownMethodData format), as returned by eth-method-registry. The name
+// is usually humanized, e.g. "Transfer From" instead of "transferFrom".
+type mmMethod struct {
+	Name   string `json:"name"`
+	Params []struct {
+		Type string `json:"type"`
+	} `json:"params"`
+}
+
+// importMetaMask reads a MetaMask data file and adds all valid selectors which
+// are not already present in the db. Two layouts are understood, both being a
+// json object with hex keys:
+//
+//   - the method registry, keyed by 4-byte selector, with either the raw
+//     signature string or a knownMethodData object as values,
+//   - the contract-metadata map, keyed by 20-byte contract address.
+//
+// The contract-metadata entries do not carry any method signatures, so they
+// are only counted and otherwise ignored.
+func importMetaMask(file string, b *abidb.Builder) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	var added, known, contracts int
+	for key, raw := range entries {
+		sig, err := abidb.ParseKey(key)
+		if err != nil {
+			log.Warn("Invalid metamask key", "key", key)
+			report.reject("malformed")
+			continue
+		}
+		if len(sig) == 20 {
+			contracts++
+			continue
+		}
+		if len(sig) != 4 {
+			log.Warn("Invalid sig, wrong length", "key", key)
+			report.reject("wrong-length")
+			continue
+		}
+		if _, exists := b.Database().Methods.Get(fmt.Sprintf("%x", sig)); exists {
+			known++
+			continue
+		}
+		selector, err := parseMetaMaskMethod(sig, raw)
+		if err != nil {
+			log.Warn("Invalid metamask entry", "key", key, "err", err)
+			report.reject("malformed")
+			continue
+		}
+		if err := b.AddMethod(sig, abidb.Entry{Signature: selector, Source: file}); err != nil {
+			log.Warn("Rejected signature", "key", key, "err", err)
+			report.reject(rejectReason(err))
+			continue
+		}
+		added++
+	}
+	log.Info("Imported metamask data", "file", file, "added", added, "known", known, "contracts", contracts)
+	return nil
+}
+
+// parseMetaMaskMethod converts a method registry value into a signature. Since
+// knownMethodData names are humanized, a few de-humanized variants of the name
+// are tried, and the first one hashing to sig is returned. If none match, the
+// most likely variant is returned and left for the verifier to reject.
+func parseMetaMaskMethod(sig []byte, raw json.RawMessage) (string, error) {
+	var selector string
+	if err := json.Unmarshal(raw, &selector); err == nil {
+		return strings.TrimSpace(selector), nil
+	}
+	var method mmMethod
+	if err := json.Unmarshal(raw, &method); err != nil {
+		return "", err
+	}
+	if method.Name == "" {
+		return "", fmt.Errorf("method name missing")
+	}
+	types := make([]string, len(method.Params))
+	for i, param := range method.Params {
+		types[i] = param.Type
+	}
+	args := "(" + strings.Join(types, ",") + ")"
+
+	joined := strings.Replace(method.Name, " ", "", -1)
+	candidates := []string{lowerFirst(joined), joined, method.Name}
+	for _, name := range candidates {
+		if bytes.Equal(crypto.Keccak256([]byte(name + args))[:4], sig) {
+			return name + args, nil
+		}
+	}
+	return candidates[0] + args, nil
+}
+
+// lowerFirst lowercases the first character of s.
+func lowerFirst(s string) string {
+	for i, r := range s {
+		return string(unicode.ToLower(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
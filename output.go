@@ -0,0 +1,162 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// encoder writes a table in a specific format. Encoders must treat the table
+// as read-only, since they are run concurrently.
+type encoder struct {
+	ext   string // file extension used when writing several formats
+	write func(t *abidb.Table, w io.Writer) (int64, error)
+}
+
+// encoders contains all supported output formats.
+var encoders = map[string]encoder{
+	"json": {".json", func(t *abidb.Table, w io.Writer) (int64, error) {
+		return t.WriteJSON(w, outputKeys, false)
+	}},
+	"json-ext": {".ext.json", func(t *abidb.Table, w io.Writer) (int64, error) {
+		return t.WriteJSON(w, outputKeys, true)
+	}},
+	"yaml": {".yaml", func(t *abidb.Table, w io.Writer) (int64, error) {
+		return t.WriteYAML(w, outputKeys, false)
+	}},
+	"yaml-ext": {".ext.yaml", func(t *abidb.Table, w io.Writer) (int64, error) {
+		return t.WriteYAML(w, outputKeys, true)
+	}},
+	"binary": {".bin", func(t *abidb.Table, w io.Writer) (int64, error) {
+		return t.WriteBinary(w)
+	}},
+	"gosrc": {".go", func(t *abidb.Table, w io.Writer) (int64, error) {
+		name := goVar
+		if t.KeySize() == 32 {
+			name += "Events"
+		}
+		return t.WriteGo(w, goPackage, name, outputKeys)
+	}},
+	"sqlite": {".sqlite", writeSQLite},
+}
+
+// outputKeys is the key format used by all encoders.
+var outputKeys abidb.KeyFormat
+
+// goPackage and goVar are the package and variable names of the generated Go
+// source. Events are written to a variable with an Events suffix, so both can
+// be generated into the same package.
+var (
+	goPackage = "fourbyte"
+	goVar     = "embedded"
+)
+
+// writeFile creates outfile, and fills it using the given write function.
+func writeFile(outfile string, write func(w io.Writer) (int64, error)) error {
+	log.Info("Saving data", "file", outfile)
+	return createFile(outfile, write)
+}
+
+// createFile is writeFile without the progress message.
+func createFile(outfile string, write func(w io.Writer) (int64, error)) error {
+	f, err := os.Create(outfile)
+	if err != nil {
+		return err
+	}
+	if _, err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// target is a single requested output.
+type target struct {
+	format string
+	file   string
+}
+
+// parseTargets resolves the comma-separated list of formats into the outputs
+// to write. A single format is written to out verbatim, whereas with several
+// formats out is used as base name, with the format extension substituted.
+func parseTargets(formats string, out string) ([]target, error) {
+	var targets []target
+	seen := make(map[string]bool)
+	for _, format := range strings.Split(formats, ",") {
+		format = strings.TrimSpace(format)
+		if _, ok := encoders[format]; !ok {
+			return nil, fmt.Errorf("unknown format %q (available: %v)", format, strings.Join(formatNames(), ", "))
+		}
+		if seen[format] {
+			continue
+		}
+		seen[format] = true
+		targets = append(targets, target{format, out})
+	}
+	if len(targets) > 1 {
+		base := strings.TrimSuffix(out, filepath.Ext(out))
+		for i := range targets {
+			targets[i].file = base + encoders[targets[i].format].ext
+		}
+	}
+	return targets, nil
+}
+
+// formatNames returns the sorted names of the supported output formats.
+func formatNames() []string {
+	var names []string
+	for name := range encoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writeOutputs encodes all targets concurrently from the shared, from then on
+// immutable, table.
+func writeOutputs(db *abidb.Table, targets []target) error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(targets))
+	)
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			write := func(w io.Writer) (int64, error) { return encoders[t.format].write(db, w) }
+			if err := writeFile(t.file, write); err != nil {
+				errs[i] = fmt.Errorf("%v output %v: %v", t.format, t.file, err)
+			}
+		}(i, t)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// The packed input format is a single text file containing one entry per
+// line, in the form
+//
+//	selector:signature
+//
+// where the selector is the hex-encoded 4-byte selector (the filename in the
+// 4bytes repository), and the signature is the file content. Colliding
+// signatures stay ;-separated, same as in the individual files. A selector may
+// also occur on several lines, the signatures of all of them collide then.
+
+// maxPackedLine is the longest line accepted from a packed file.
+const maxPackedLine = 1024 * 1024
+
+// parsePackedLine parses a single line of a packed file.
+func parsePackedLine(line int, text string) ([]byte, string, error) {
+	kv := strings.SplitN(text, ":", 2)
+	if len(kv) != 2 {
+		return nil, "", fmt.Errorf("missing separator")
+	}
+	sig, err := abidb.ParseKey(kv[0])
+	if err != nil {
+		return nil, "", err
+	}
+	return sig, kv[1], nil
+}
+
+// runPack implements the pack command, which converts a 4bytes signature
+// directory into a packed file. The content is not validated, that is left
+// to the build reading the packed file.
+func runPack(args []string) error {
+	fs := flag.NewFlagSet("pack", flag.ExitOnError)
+	in := fs.String("i", "", "input directory to read")
+	out := fs.String("o", "", "packed file to write to (overwrites if exists)")
+	fs.Parse(args)
+	if *in == "" || *out == "" {
+		return fmt.Errorf("both input directory and output file must be given")
+	}
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	names, err := f.Readdirnames(-1)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	outf, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(outf)
+	var packed int
+	for _, name := range names {
+		// Only bother with signature files
+		if _, err := abidb.ParseKey(name); err != nil {
+			continue
+		}
+		dat, err := ioutil.ReadFile(filepath.Join(*in, name))
+		if err != nil {
+			log.Warn("Failed to read signature file", "file", name, "err", err)
+			continue
+		}
+		content := strings.TrimSpace(string(dat))
+		if strings.ContainsAny(content, "\r\n") {
+			log.Warn("Skipping multi-line file", "file", name)
+			continue
+		}
+		fmt.Fprintf(w, "%s:%s\n", strings.ToLower(name), content)
+		packed++
+	}
+	if err := w.Flush(); err != nil {
+		outf.Close()
+		return err
+	}
+	log.Info("Packed signature files", "files", packed, "file", *out)
+	return outf.Close()
+}
@@ -0,0 +1,264 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// record is a single raw input entry, the signatures claimed for a selector or
+// event topic. Records flow through the validation pipeline, and are committed
+// to the builder in the order they were produced.
+type record struct {
+	key       []byte        // claimed selector or topic
+	data      string        // ;-separated signatures, unless read from file
+	file      string        // file to read the signatures from, if any
+	keepFirst bool          // whether an already present entry takes precedence
+	repeated  bool          // whether the key occurred earlier in the same input
+	input     string        // input the record was read from
+	source    string        // file, or file and line, the record was read from
+	msg       string        // message to log in order, instead of processing
+	ctx       []interface{} // context of the message
+	kind      string        // kind of problem the message reports
+	skip      bool          // whether the record only accounts for progress
+	size      int64         // progress units this record represents
+
+	// Filled in by the workers
+	index   int
+	checked []*abidb.Checked // one per signature
+	err     error
+}
+
+// process reads and validates the record. It is run concurrently.
+func (r *record) process(b *abidb.Builder) {
+	if r.msg != "" || r.skip {
+		return
+	}
+	if r.file != "" {
+		dat, err := ioutil.ReadFile(r.file)
+		if err != nil {
+			r.err = err
+			return
+		}
+		r.data = string(dat)
+	}
+	for _, sig := range strings.Split(r.data, ";") {
+		r.checked = append(r.checked, b.Check(r.key, abidb.Entry{Signature: strings.TrimSpace(sig)}))
+	}
+}
+
+// commit adds the processed record to the builder, logging any problems. It is
+// never run concurrently.
+func (r *record) commit(b *abidb.Builder) {
+	switch {
+	case r.skip:
+		return
+	case r.msg != "":
+		log.Warn(r.msg, append([]interface{}{"source", r.source}, r.ctx...)...)
+		report.reject(r.kind)
+		return
+	case r.err != nil:
+		log.Warn("Failed to read signature file", "file", r.file, "err", r.err)
+		report.reject("unreadable")
+		return
+	}
+	if r.keepFirst {
+		if existing, ok := b.Database().Table(r.key).Get(fmt.Sprintf("%x", r.key)); ok {
+			if !r.valid() {
+				// Invalid entries are rejected, whether or not they would be kept
+				if err := b.Commit(r.checked[0]); err != nil {
+					log.Warn("Rejected signature", "source", r.source, "err", err)
+					report.reject(rejectReason(err))
+				}
+				return
+			}
+			if r.repeated && (merge == nil || merge.origins[fmt.Sprintf("%x", r.key)] == r.input) {
+				r.commitRepeated(b, existing)
+				return
+			}
+			if existing.Signature != r.checked[0].Entry.Signature {
+				log.Info("Skipping repeated selector", "sig", fmt.Sprintf("%x", r.key), "have", existing.Signature, "skipped", r.data)
+			}
+			if merge != nil && r.checked[0].Err == nil {
+				merge.skipped(r.key, existing, r.input, r.checked[0].Entry.Signature)
+			}
+			return
+		}
+	}
+	c := r.checked[0]
+	if len(r.checked) > 1 {
+		c = collisions.resolve(r.key, r.checked)
+	}
+	c.Entry.Source = r.source
+	if r.file != "" {
+		c.Entry.Source = r.file
+	}
+	if err := b.Commit(c); err != nil {
+		log.Warn("Rejected signature", "source", r.source, "err", err)
+		report.reject(rejectReason(err))
+		return
+	}
+	if c.Original != "" {
+		log.Debug("Canonicalized signature", "source", r.source, "claimed", c.Original, "stored", c.Entry.Signature)
+	}
+	if merge != nil {
+		merge.added(r.key, r.input)
+	}
+}
+
+// valid reports whether any of the signatures of the record passed validation.
+func (r *record) valid() bool {
+	for _, c := range r.checked {
+		if c.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// commitRepeated resolves the signatures of a key listed again in the same
+// input together with the ones stored already, as if they were all listed on
+// a single line. Repeating known signatures changes nothing.
+func (r *record) commitRepeated(b *abidb.Builder, existing *abidb.Entry) {
+	var (
+		candidates []*abidb.Checked
+		known      = make(map[string]bool)
+		fresh      bool
+	)
+	for _, sig := range existing.Signatures() {
+		e := *existing
+		e.Signature, e.Collisions = sig, nil
+		candidates = append(candidates, &abidb.Checked{Key: r.key, Entry: e})
+		known[sig] = true
+	}
+	for _, c := range r.checked {
+		if c.Err == nil && !known[c.Entry.Signature] {
+			fresh = true
+		}
+		candidates = append(candidates, c)
+	}
+	if !fresh {
+		return
+	}
+	if err := b.Commit(collisions.resolve(r.key, candidates)); err != nil {
+		log.Warn("Rejected signature", "source", r.source, "err", err)
+		report.reject(rejectReason(err))
+	}
+}
+
+// jobs is the number of concurrent validation workers.
+var jobs = 1
+
+// processRecords runs the validation pipeline: produce emits the records in
+// input order, which are then read and validated concurrently on the workers,
+// and finally passed to commit in the original order. Since commits are
+// ordered, the result, and even the output printed, is identical to a
+// sequential run. The total is the sum of record sizes, used for progress
+// reporting.
+func processRecords(b *abidb.Builder, total int64, produce func(emit func(*record)) error, commit func(*record)) error {
+	var (
+		pending = make(chan *record, jobs*16)
+		done    = make(chan *record, jobs*16)
+		slots   = make(chan struct{}, jobs*256) // bounds the reordering buffer
+		wg      sync.WaitGroup
+		perr    error
+	)
+	// Start the producer, numbering the records
+	go func() {
+		var index int
+		perr = produce(func(r *record) {
+			slots <- struct{}{}
+			r.index = index
+			index++
+			pending <- r
+		})
+		close(pending)
+	}()
+	// Start the workers, closing the result channel when done
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range pending {
+				r.process(b)
+				done <- r
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	// Commit the results in order
+	var (
+		next     int
+		buffered = make(map[int]*record)
+		prog     = newProgress(total)
+	)
+	for r := range done {
+		buffered[r.index] = r
+		for {
+			r, ok := buffered[next]
+			if !ok {
+				break
+			}
+			delete(buffered, next)
+			next++
+			commit(r)
+			prog.advance(r.size)
+			<-slots
+		}
+	}
+	prog.finish()
+	return perr
+}
+
+// progress reports the pipeline progress with an estimated time of arrival.
+type progress struct {
+	total, done int64
+	start, last time.Time
+}
+
+func newProgress(total int64) *progress {
+	now := time.Now()
+	return &progress{total: total, start: now, last: now}
+}
+
+// advance marks some units done, and logs the progress every few seconds.
+func (p *progress) advance(n int64) {
+	p.done += n
+	if time.Since(p.last) < 5*time.Second || p.total == 0 {
+		return
+	}
+	p.last = time.Now()
+	elapsed := p.last.Sub(p.start)
+	eta := time.Duration(float64(elapsed) * float64(p.total-p.done) / float64(p.done))
+	log.Info("Processing input", "done", fmt.Sprintf("%.1f%%", 100*float64(p.done)/float64(p.total)),
+		"elapsed", elapsed.Round(time.Second), "eta", eta.Round(time.Second))
+}
+
+// finish logs the total time taken.
+func (p *progress) finish() {
+	log.Info("Processed input", "elapsed", time.Since(p.start).Round(time.Millisecond))
+}
@@ -0,0 +1,182 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// pipelineResult is everything a build produces, which must not depend on the
+// number of workers.
+type pipelineResult struct {
+	commits    []string // sources of the records, in commit order
+	methods    string   // extended json output
+	collisions []collision
+	conflicts  []conflict
+	rejected   map[string]int
+	mismatches []string
+}
+
+// buildPipeline reads the inputs with the given number of workers.
+func buildPipeline(t *testing.T, workers int, inputs []input) *pipelineResult {
+	defer func(j int, c *collisionPolicy, r *buildReport, m *crossMerge) {
+		jobs, collisions, report, merge = j, c, r, m
+	}(jobs, collisions, report, merge)
+
+	var err error
+	jobs = workers
+	if collisions, err = newCollisionPolicy("all", ""); err != nil {
+		t.Fatal(err)
+	}
+	report = &buildReport{Rejected: make(map[string]int), start: time.Now()}
+	merge = newCrossMerge()
+
+	res := new(pipelineResult)
+	b := abidb.NewBuilder()
+	for _, in := range inputs {
+		err := readInput(in, b, func(r *record) {
+			res.commits = append(res.commits, r.source)
+			r.commit(b)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := b.Database().Methods.WriteJSON(&buf, abidb.KeyFormat{}, true); err != nil {
+		t.Fatal(err)
+	}
+	res.methods = buf.String()
+	res.collisions = collisions.report
+	res.conflicts = merge.conflicts
+	res.rejected = report.Rejected
+	for _, m := range b.Mismatches() {
+		res.mismatches = append(res.mismatches, m.Error())
+	}
+	return res
+}
+
+// Tests that the concurrently validated records are committed in input order,
+// so the result is identical to a sequential build.
+func TestProcessRecordsOrdered(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	selector := func(sig string) string { return fmt.Sprintf("%x", crypto.Keccak256([]byte(sig))[:4]) }
+	var (
+		files  = make(map[string]string)
+		packed strings.Builder
+	)
+	for i := 0; i < 300; i++ {
+		sig := fmt.Sprintf("f%d(uint256)", i)
+		files["dir/"+selector(sig)] = sig
+	}
+	files["dir/095ea7b3"] = "approve(address,uint256)"
+	for i := 0; i < 2000; i++ {
+		switch {
+		case i%13 == 0:
+			// Hash mismatches
+			fmt.Fprintf(&packed, "%08x:g%d()\n", i, i)
+		case i%17 == 0:
+			// Collisions, split over several lines
+			fmt.Fprintf(&packed, "a9059cbb:transfer(address,uint256)\n")
+			fmt.Fprintf(&packed, "a9059cbb:many_msg_babbage(bytes1)\n")
+		case i%19 == 0:
+			// Conflicts with the directory
+			fmt.Fprintf(&packed, "095ea7b3:sign_szabo_bytecode(bytes16,uint128)\n")
+		default:
+			// Repeating entries, some of them in the directory too
+			sig := fmt.Sprintf("f%d(uint256)", i%700)
+			fmt.Fprintf(&packed, "%s:%s\n", selector(sig), sig)
+		}
+	}
+	files["packed.txt"] = packed.String()
+	writeFiles(t, dir, files)
+
+	inputs := []input{
+		{path: filepath.Join(dir, "dir"), format: "4bytes"},
+		{path: filepath.Join(dir, "packed.txt"), format: "packed"},
+	}
+	want := buildPipeline(t, 1, inputs)
+	if len(want.collisions) == 0 || len(want.conflicts) == 0 || len(want.mismatches) == 0 {
+		t.Fatalf("test data lacks collisions (%d), conflicts (%d) or mismatches (%d)",
+			len(want.collisions), len(want.conflicts), len(want.mismatches))
+	}
+	for i := 301; i < len(want.commits); i++ {
+		if have := fmt.Sprintf("%s:%d", inputs[1].path, i-300); want.commits[i] != have {
+			t.Fatalf("commit %d: have %s, want %s", i, want.commits[i], have)
+		}
+	}
+	for run := 0; run < 3; run++ {
+		have := buildPipeline(t, 8, inputs)
+		if !reflect.DeepEqual(have, want) {
+			for i := range want.commits {
+				if i >= len(have.commits) || have.commits[i] != want.commits[i] {
+					t.Errorf("first differing commit %d", i)
+					break
+				}
+			}
+			t.Fatalf("run %d: result with 8 workers differs from the sequential one", run)
+		}
+	}
+}
+
+// Tests that invalid entries for keys present already are rejected too, instead
+// of being dropped as duplicates.
+func TestInvalidDuplicates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pipeline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"dir/a9059cbb": "transfer(address,uint256)",
+		"packed.txt":   "a9059cbb:transfer(address,uint257)\n70a08231:balanceOf(address)\n70a08231:balanceOf(addres)\n70a08231:balanceOf(uint256)\n",
+	})
+	inputs := []input{
+		{path: filepath.Join(dir, "dir"), format: "4bytes"},
+		{path: filepath.Join(dir, "packed.txt"), format: "packed"},
+	}
+	for _, workers := range []int{1, 8} {
+		res := buildPipeline(t, workers, inputs)
+		if want := map[string]int{"hash-mismatch": 3}; !reflect.DeepEqual(res.rejected, want) {
+			t.Errorf("%d workers: have rejected %v, want %v", workers, res.rejected, want)
+		}
+		if len(res.mismatches) != 3 {
+			t.Errorf("%d workers: have %d mismatches, want 3: %v", workers, len(res.mismatches), res.mismatches)
+		}
+		for _, sig := range []string{`"transfer(address,uint256)"`, `"balanceOf(address)"`} {
+			if !strings.Contains(res.methods, sig) {
+				t.Errorf("%d workers: %s missing from output:\n%s", workers, sig, res.methods)
+			}
+		}
+	}
+}
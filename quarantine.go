@@ -0,0 +1,58 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// quarantined is a single entry whose claimed selector does not match the
+// keccak hash of its signature.
+type quarantined struct {
+	Selector  string `json:"selector"`  // selector claimed by the source (filename)
+	Signature string `json:"signature"` // signature found in the source
+	Computed  string `json:"computed"`  // selector computed from the signature
+}
+
+// writeQuarantine saves the hash-mismatched entries, sorted by claimed selector,
+// as a json list to the given file, so they can be attached to an upstream bug
+// report, or fed back in after a normalization fix.
+func writeQuarantine(mismatches []*abidb.MismatchError, outfile string) error {
+	entries := make([]quarantined, 0, len(mismatches))
+	for _, m := range mismatches {
+		entries = append(entries, quarantined{
+			Selector:  fmt.Sprintf("%x", m.Key),
+			Signature: m.Signature,
+			Computed:  fmt.Sprintf("%x", m.Computed),
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Selector < entries[j].Selector
+	})
+	data, err := json.MarshalIndent(entries, "", " ")
+	if err != nil {
+		return err
+	}
+	log.Info("Saving quarantined entries", "entries", len(entries), "file", outfile)
+	return ioutil.WriteFile(outfile, data, 0644)
+}
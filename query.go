@@ -0,0 +1,216 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// runQuery implements the query command, which resolves selectors (or event
+// topics) against a built database, and decodes the arguments of calldata.
+func runQuery(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ExitOnError)
+	dbFile := fs.String("db", "", "database to query, in any of the json, binary or sqlite formats")
+	calldata := fs.String("calldata", "", "hex calldata to decode (optional)")
+	fs.Parse(args)
+	if *dbFile == "" {
+		return fmt.Errorf("database not given")
+	}
+	if fs.NArg() == 0 && *calldata == "" {
+		return fmt.Errorf("neither selector nor calldata given")
+	}
+	db, err := loadDatabase(*dbFile)
+	if err != nil {
+		return err
+	}
+	for _, arg := range fs.Args() {
+		key, err := abidb.ParseKey(arg)
+		if err != nil {
+			return fmt.Errorf("invalid selector %q: %v", arg, err)
+		}
+		e, ok := db.Get(fmt.Sprintf("%x", key))
+		if !ok {
+			fmt.Printf("%x: unknown\n", key)
+			continue
+		}
+		for _, sig := range e.Signatures() {
+			fmt.Printf("%x: %v\n", key, sig)
+		}
+	}
+	if *calldata != "" {
+		data, err := abidb.ParseKey(*calldata)
+		if err != nil {
+			return fmt.Errorf("invalid calldata: %v", err)
+		}
+		return decodeCalldata(db, data)
+	}
+	return nil
+}
+
+// decodeCalldata prints the arguments of the calldata, as decoded by each of
+// the signatures known for its selector.
+func decodeCalldata(db *abidb.Table, data []byte) error {
+	if len(data) < 4 {
+		return fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	e, ok := db.Get(fmt.Sprintf("%x", data[:4]))
+	if !ok {
+		return fmt.Errorf("unknown selector %x", data[:4])
+	}
+	if printCalls(decodeCall(db, e.Signatures(), data), "") == 0 {
+		return fmt.Errorf("no signature of %x matches the calldata", data[:4])
+	}
+	return nil
+}
+
+// printCalls prints the decoded calls, and the calls of the user operations
+// they bundle, further indented. It returns the number of matching signatures.
+func printCalls(calls []decodedCall, indent string) int {
+	var decoded int
+	for _, call := range calls {
+		if call.Error != "" {
+			fmt.Printf("%s%v: %v\n", indent, call.Signature, call.Error)
+			continue
+		}
+		fmt.Printf("%s%v\n", indent, call.Signature)
+		for i, arg := range call.Args {
+			fmt.Printf("%s - %d %v: %v\n", indent, i, arg.Type, arg.Value)
+		}
+		for i, op := range call.Operations {
+			fmt.Printf("%s - operation %d of %v\n", indent, i, op.Sender)
+			if op.Error != "" {
+				fmt.Printf("%s   %v\n", indent, op.Error)
+			}
+			printCalls(op.Calls, indent+"   ")
+		}
+		decoded++
+	}
+	return decoded
+}
+
+// decodedCall is calldata as decoded by one of the signatures of its selector.
+type decodedCall struct {
+	Signature string       `json:"signature"`
+	Args      []decodedArg `json:"args,omitempty"`
+	Error     string       `json:"error,omitempty"` // why the signature doesn't match
+
+	Operations []userOperation `json:"operations,omitempty"` // bundled by an ERC-4337 entry point
+}
+
+// userOperation is an ERC-4337 user operation of a handleOps bundle, with the
+// call its sender executes decoded.
+type userOperation struct {
+	Sender string        `json:"sender"`
+	Calls  []decodedCall `json:"calls,omitempty"`
+	Error  string        `json:"error,omitempty"` // why the call can't be decoded
+}
+
+// entryPoints are the canonical handleOps signatures of the ERC-4337 entry
+// point versions, mapped to the index of the callData field of their user
+// operation tuples. The sender is the first field in all versions.
+var entryPoints = map[string]int{
+	// v0.6 UserOperation
+	"handleOps((address,uint256,bytes,bytes,uint256,uint256,uint256,uint256,uint256,bytes,bytes)[],address)": 3,
+	// v0.7 PackedUserOperation
+	"handleOps((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes)[],address)": 3,
+}
+
+// decodedArg is a single decoded argument.
+type decodedArg struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// decodeCall decodes the arguments of the calldata with each of the signatures
+// known for its selector. Bundles of ERC-4337 user operations are unwrapped,
+// and the call of each operation decoded against the database too.
+func decodeCall(db lookupTable, sigs []string, data []byte) []decodedCall {
+	var calls []decodedCall
+	for _, sig := range sigs {
+		call := decodedCall{Signature: sig}
+		m, err := abidb.Method(sig)
+		if err != nil {
+			call.Error = err.Error()
+			calls = append(calls, call)
+			continue
+		}
+		values, err := m.Inputs.Unpack(data[4:])
+		if err != nil {
+			call.Error = fmt.Sprintf("cannot decode: %v", err)
+			calls = append(calls, call)
+			continue
+		}
+		for i, val := range values {
+			call.Args = append(call.Args, decodedArg{m.Inputs[i].Type.String(), formatValue(val)})
+		}
+		if field, ok := entryPoints[m.Sig]; ok {
+			call.Operations = decodeUserOps(db, values[0], field)
+		}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// decodeUserOps decodes the calls of the user operations unpacked from a
+// handleOps bundle, the callData being the given field of their tuples.
+func decodeUserOps(db lookupTable, unpacked interface{}, field int) []userOperation {
+	ops := reflect.ValueOf(unpacked)
+	decoded := make([]userOperation, ops.Len())
+	for i := range decoded {
+		var (
+			op     = ops.Index(i)
+			sender = op.Field(0).Interface().(common.Address)
+			data   = op.Field(field).Bytes()
+		)
+		decoded[i].Sender = sender.Hex()
+		switch {
+		case len(data) == 0:
+			// Only deploys the account, or pays for nothing
+		case len(data) < 4:
+			decoded[i].Error = fmt.Sprintf("calldata too short: %d bytes", len(data))
+		default:
+			sigs, ok := db.Lookup(data[:4])
+			if !ok {
+				decoded[i].Error = fmt.Sprintf("unknown selector %x", data[:4])
+				break
+			}
+			decoded[i].Calls = decodeCall(db, sigs, data)
+		}
+	}
+	return decoded
+}
+
+// formatValue formats a decoded argument, printing byte arrays and slices as
+// hex instead of lists of numbers.
+func formatValue(val interface{}) string {
+	v := reflect.ValueOf(val)
+	switch {
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		return hexutil.Encode(v.Bytes())
+	case v.Kind() == reflect.Array && v.Type().Elem().Kind() == reflect.Uint8:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return hexutil.Encode(b)
+	}
+	return fmt.Sprintf("%v", val)
+}
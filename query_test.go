@@ -0,0 +1,126 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// testTable returns a selector table of the given signatures.
+func testTable(sigs ...string) *abidb.Table {
+	t := abidb.NewTable(4)
+	for _, sig := range sigs {
+		m, err := abidb.Method(sig)
+		if err != nil {
+			panic(err)
+		}
+		t.Set(fmt.Sprintf("%x", m.ID), &abidb.Entry{Signature: sig})
+	}
+	return t
+}
+
+// pack encodes a call of the signature with the given arguments.
+func pack(sig string, args ...interface{}) []byte {
+	m, err := abidb.Method(sig)
+	if err != nil {
+		panic(err)
+	}
+	data, err := m.Inputs.Pack(args...)
+	if err != nil {
+		panic(err)
+	}
+	return append(m.ID, data...)
+}
+
+func TestDecodeUserOperations(t *testing.T) {
+	const handleOps = "handleOps((address,uint256,bytes,bytes,bytes32,uint256,bytes32,bytes,bytes)[],address)"
+	var (
+		db       = testTable(handleOps, "execute(address,uint256,bytes)", "transfer(address,uint256)")
+		sender   = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		token    = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		transfer = pack("transfer(address,uint256)", sender, big.NewInt(5))
+		execute  = pack("execute(address,uint256,bytes)", token, big.NewInt(0), transfer)
+	)
+	// Assemble the user operations, only the sender and callData matter
+	m, _ := abidb.Method(handleOps)
+	ops := reflect.MakeSlice(reflect.SliceOf(m.Inputs[0].Type.Elem.TupleType), 0, 3)
+	for _, data := range [][]byte{execute, nil, {0xde, 0xad, 0xbe, 0xef}} {
+		op := reflect.New(m.Inputs[0].Type.Elem.TupleType).Elem()
+		op.Field(0).Set(reflect.ValueOf(sender))
+		op.Field(1).Set(reflect.ValueOf(new(big.Int)))
+		op.Field(3).SetBytes(data)
+		op.Field(5).Set(reflect.ValueOf(new(big.Int)))
+		ops = reflect.Append(ops, op)
+	}
+	data := pack(handleOps, ops.Interface(), sender)
+
+	calls := decodeCall(db, []string{handleOps}, data)
+	if len(calls) != 1 || calls[0].Error != "" {
+		t.Fatalf("handleOps not decoded: %+v", calls)
+	}
+	have := calls[0].Operations
+	if len(have) != 3 {
+		t.Fatalf("have %d operations, want 3", len(have))
+	}
+	for i, op := range have {
+		if op.Sender != sender.Hex() {
+			t.Errorf("operation %d: have sender %v, want %v", i, op.Sender, sender.Hex())
+		}
+	}
+	if len(have[0].Calls) != 1 || have[0].Calls[0].Signature != "execute(address,uint256,bytes)" {
+		t.Fatalf("operation 0: call not decoded: %+v", have[0])
+	}
+	if arg := have[0].Calls[0].Args[2].Value; arg != "0x"+hex.EncodeToString(transfer) {
+		t.Errorf("operation 0: have inner calldata %v", arg)
+	}
+	if len(have[1].Calls) != 0 || have[1].Error != "" {
+		t.Errorf("operation 1: empty calldata decoded: %+v", have[1])
+	}
+	if have[2].Error != "unknown selector deadbeef" {
+		t.Errorf("operation 2: have error %q", have[2].Error)
+	}
+	// Plain calls are not unwrapped
+	if calls := decodeCall(db, []string{"execute(address,uint256,bytes)"}, execute); len(calls[0].Operations) != 0 {
+		t.Errorf("plain call unwrapped: %+v", calls[0])
+	}
+}
+
+func TestEntryPointSelectors(t *testing.T) {
+	want := map[string]string{
+		"1fad948c": "v0.6",
+		"765e827f": "v0.7",
+	}
+	for sig := range entryPoints {
+		m, err := abidb.Method(sig)
+		if err != nil {
+			t.Fatalf("%s: %v", sig, err)
+		}
+		if m.Sig != sig {
+			t.Errorf("%s: not canonical, have %s", sig, m.Sig)
+		}
+		if _, ok := want[fmt.Sprintf("%x", m.ID)]; !ok {
+			t.Errorf("%s: unexpected selector %x", sig, m.ID)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// maxDecodeBody is the largest decode request accepted.
+const maxDecodeBody = 1024 * 1024
+
+// runServe implements the serve command, which exposes a built database over
+// http:
+//
+//	GET  /selector/{selector}  signatures of a selector (or topic)
+//	POST /decode               {"calldata":"0x..."} decoded by all its signatures
+//
+// With -reload, the database is reloaded whenever its file changes.
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	dbFile := fs.String("db", "", "database to serve, in any of the json, binary or sqlite formats")
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	reload := fs.Bool("reload", false, "reload the database when its file changes")
+	compact := fs.Bool("compact", false, "keep the database interned in memory, using a fraction of the memory for slower lookups")
+	fs.Parse(args)
+	if *dbFile == "" {
+		return fmt.Errorf("database not given")
+	}
+	s := &server{file: *dbFile, compact: *compact}
+	if err := s.load(); err != nil {
+		return err
+	}
+	if *reload {
+		go func() {
+			err := s.watch()
+			log.Error("Stopped reloading database", "file", s.file, "err", err)
+		}()
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/selector/", s.handleSelector)
+	mux.HandleFunc("/decode", s.handleDecode)
+
+	log.Info("Serving database", "addr", *addr, "entries", s.table().Len())
+	return http.ListenAndServe(*addr, mux)
+}
+
+// lookupTable is a database as served, either as read or in compact form.
+type lookupTable interface {
+	Lookup(key []byte) ([]string, bool)
+	Len() int
+}
+
+// server serves lookups from a database, which may be replaced while serving.
+type server struct {
+	file    string
+	compact bool // whether to serve the compact form of the database
+	db      lookupTable
+	lock    sync.RWMutex
+}
+
+// table returns the database currently served.
+func (s *server) table() lookupTable {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.db
+}
+
+// load reads the database file, and serves it from then on.
+func (s *server) load() error {
+	var db lookupTable
+	if s.compact {
+		c, err := loadCompact(s.file)
+		if err != nil {
+			return err
+		}
+		log.Info("Compacted database", "entries", c.Len(), "tokens", c.Tokens(), "size", common.StorageSize(c.Size()))
+		db = c
+	} else {
+		t, err := loadDatabase(s.file)
+		if err != nil {
+			return err
+		}
+		db = t
+	}
+	s.lock.Lock()
+	s.db = db
+	s.lock.Unlock()
+	return nil
+}
+
+// watch reloads the database whenever its file is written or replaced. Failed
+// reloads keep serving the previous one. It only returns on failure.
+func (s *server) watch() error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	// Watch the directory, since builds may replace the file instead of writing it
+	if err := w.Add(filepath.Dir(s.file)); err != nil {
+		return err
+	}
+	var timer <-chan time.Time // fires once changes settled, nil if none pending
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return fmt.Errorf("watcher closed")
+			}
+			if filepath.Clean(ev.Name) == filepath.Clean(s.file) && ev.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) != 0 {
+				timer = time.After(watchDelay)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return fmt.Errorf("watcher closed")
+			}
+			return err
+
+		case <-timer:
+			timer = nil
+			if err := s.load(); err != nil {
+				log.Warn("Failed to reload database", "file", s.file, "err", err)
+				continue
+			}
+			log.Info("Reloaded database", "file", s.file, "entries", s.table().Len())
+		}
+	}
+}
+
+// selectorResponse is the reply of a selector lookup.
+type selectorResponse struct {
+	Selector   string   `json:"selector"`
+	Signatures []string `json:"signatures"`
+}
+
+// decodeRequest is the body of a decode request.
+type decodeRequest struct {
+	Calldata string `json:"calldata"`
+}
+
+// decodeResponse is the reply of a decode request, with one decoding per known
+// signature of the selector.
+type decodeResponse struct {
+	Selector string        `json:"selector"`
+	Calls    []decodedCall `json:"calls"`
+}
+
+// errorResponse is the reply of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *server) handleSelector(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		reply(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+		return
+	}
+	key, err := abidb.ParseKey(strings.TrimPrefix(r.URL.Path, "/selector/"))
+	if err != nil {
+		reply(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid selector: %v", err)})
+		return
+	}
+	sigs, ok := s.table().Lookup(key)
+	if !ok {
+		reply(w, http.StatusNotFound, errorResponse{fmt.Sprintf("unknown selector %x", key)})
+		return
+	}
+	reply(w, http.StatusOK, selectorResponse{fmt.Sprintf("0x%x", key), sigs})
+}
+
+func (s *server) handleDecode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		reply(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+		return
+	}
+	var req decodeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxDecodeBody)).Decode(&req); err != nil {
+		reply(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid request: %v", err)})
+		return
+	}
+	data, err := abidb.ParseKey(req.Calldata)
+	if err != nil {
+		reply(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("invalid calldata: %v", err)})
+		return
+	}
+	if len(data) < 4 {
+		reply(w, http.StatusBadRequest, errorResponse{fmt.Sprintf("calldata too short: %d bytes", len(data))})
+		return
+	}
+	db := s.table()
+	sigs, ok := db.Lookup(data[:4])
+	if !ok {
+		reply(w, http.StatusNotFound, errorResponse{fmt.Sprintf("unknown selector %x", data[:4])})
+		return
+	}
+	reply(w, http.StatusOK, decodeResponse{fmt.Sprintf("0x%x", data[:4]), decodeCall(db, sigs, data)})
+}
+
+// reply writes the response as json.
+func reply(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Debug("Failed to write response", "err", err)
+	}
+}
+
+// loadCompact reads a database like loadDatabase, straight into compact form.
+// Only sqlite databases are read into a full table first.
+func loadCompact(file string) (*abidb.CompactTable, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	if head, _ := r.Peek(len(sqliteMagic)); isSQLite(head) {
+		t, err := readSQLite(file)
+		if err != nil {
+			return nil, err
+		}
+		return t.Compact(), nil
+	}
+	return abidb.ReadCompact(r)
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// shardPrefix is the number of leading hex digits of the key to shard the
+// output by, or 0 to write it unsharded.
+var shardPrefix int
+
+// maxShardPrefix limits the number of shard files to 16^4.
+const maxShardPrefix = 4
+
+// shardIndex is the manifest written next to the shards, listing the files
+// holding the keys with each prefix.
+type shardIndex struct {
+	PrefixLength int          `json:"prefixLength"`
+	Formats      []string     `json:"formats"`
+	Total        int          `json:"total"`
+	Shards       []shardEntry `json:"shards"`
+}
+
+// shardEntry is a single shard in the index.
+type shardEntry struct {
+	Prefix string   `json:"prefix"`
+	Count  int      `json:"count"`
+	Files  []string `json:"files"`
+}
+
+// checkShardable returns an error if any of the targets cannot be sharded.
+func checkShardable(targets []target) error {
+	for _, t := range targets {
+		if shardPrefix > 0 && t.format == "gosrc" {
+			return fmt.Errorf("gosrc output cannot be sharded, the shards would share their declarations")
+		}
+	}
+	return nil
+}
+
+// writeTable writes the table to all targets, or if sharding, into the out
+// directory as one file per key prefix and format.
+func writeTable(db *abidb.Table, targets []target, out string) error {
+	if shardPrefix == 0 {
+		return writeOutputs(db, targets)
+	}
+	return writeShards(db, targets, out)
+}
+
+// writeShards splits the table by key prefix, and writes every shard in the
+// formats of all targets to the given directory, together with an index.json
+// manifest. All 16^shardPrefix shards are written, also empty ones, so that a
+// consumer can load the shard of any key without consulting the index.
+func writeShards(db *abidb.Table, targets []target, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	// Split the table, keys are normalized lowercase hex
+	var (
+		count    = 1 << (4 * uint(shardPrefix))
+		prefixes = make([]string, count)
+		shards   = make([]*abidb.Table, count)
+		index    = make(map[string]int, count)
+	)
+	for i := range shards {
+		prefixes[i] = fmt.Sprintf("%0*x", shardPrefix, i)
+		shards[i] = abidb.NewTable(db.KeySize())
+		index[prefixes[i]] = i
+	}
+	for _, key := range db.Keys() {
+		e, _ := db.Get(key)
+		shards[index[key[:shardPrefix]]].Set(key, e)
+	}
+	log.Info("Saving shards", "entries", db.Len(), "shards", count, "dir", dir)
+
+	// Write all formats concurrently, each shard by shard
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(targets))
+	)
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t target) {
+			defer wg.Done()
+			enc := encoders[t.format]
+			for j, shard := range shards {
+				file := filepath.Join(dir, prefixes[j]+enc.ext)
+				write := func(w io.Writer) (int64, error) { return enc.write(shard, w) }
+				if err := createFile(file, write); err != nil {
+					errs[i] = fmt.Errorf("%v output %v: %v", t.format, file, err)
+					return
+				}
+			}
+		}(i, t)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	// Write the manifest after the shards, so it is complete if present
+	manifest := shardIndex{PrefixLength: shardPrefix, Total: db.Len()}
+	for _, t := range targets {
+		manifest.Formats = append(manifest.Formats, t.format)
+	}
+	for i, shard := range shards {
+		entry := shardEntry{Prefix: prefixes[i], Count: shard.Len()}
+		for _, t := range targets {
+			entry.Files = append(entry.Files, prefixes[i]+encoders[t.format].ext)
+		}
+		manifest.Shards = append(manifest.Shards, entry)
+	}
+	data, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "index.json"), data, 0644)
+}
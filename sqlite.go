@@ -0,0 +1,172 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/holiman/abidbbuilder/abidb"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteMagic is the header every sqlite database file starts with.
+const sqliteMagic = "SQLite format 3\x00"
+
+// sqliteTable returns the name of the sqlite table holding the entries of t:
+// selectors for methods, and events for topics. Both share the same schema.
+func sqliteTable(t *abidb.Table) string {
+	if t.KeySize() == 32 {
+		return "events"
+	}
+	return "selectors"
+}
+
+// writeSQLite writes the table as a sqlite database, for consumers querying it
+// in place. Colliding signatures are ;-joined, same as in the binary format.
+// Entries without a known build time get the current one. Since sqlite needs a
+// file of its own, the database is built in a temporary file, which is then
+// copied to w.
+func writeSQLite(t *abidb.Table, w io.Writer) (int64, error) {
+	dir, err := ioutil.TempDir("", "abidb-sqlite")
+	if err != nil {
+		return 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "db.sqlite")
+	if err := createSQLite(t, file); err != nil {
+		return 0, err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return io.Copy(w, f)
+}
+
+// createSQLite creates a new sqlite database at file, containing the table.
+func createSQLite(t *abidb.Table, file string) error {
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	name := sqliteTable(t)
+	schema := []string{
+		fmt.Sprintf("CREATE TABLE %s (sig TEXT PRIMARY KEY, signature TEXT NOT NULL, source TEXT, added_at INTEGER)", name),
+		fmt.Sprintf("CREATE INDEX %[1]s_signature ON %[1]s (signature)", name),
+		fmt.Sprintf("CREATE INDEX %[1]s_source ON %[1]s (source)", name),
+		fmt.Sprintf("CREATE INDEX %[1]s_added_at ON %[1]s (added_at)", name),
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	insert, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (sig, signature, source, added_at) VALUES (?, ?, ?, ?)", name))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	now := time.Now().Unix()
+	for _, key := range t.Keys() {
+		e, _ := t.Get(key)
+		added := e.AddedAt
+		if added == 0 {
+			added = now
+		}
+		if _, err := insert.Exec(outputKeys.Format(key), strings.Join(e.Signatures(), ";"), e.Source, added); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// readSQLite reads a database written by writeSQLite, retaining the sources and
+// build times of the entries.
+func readSQLite(file string) (*abidb.Table, error) {
+	db, err := sql.Open("sqlite3", "file:"+file+"?mode=ro")
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// The file contains either methods or events, but not both
+	var name string
+	if err := db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'table' AND name IN ('selectors', 'events')").Scan(&name); err != nil {
+		return nil, fmt.Errorf("no selectors or events table: %v", err)
+	}
+	rows, err := db.Query(fmt.Sprintf("SELECT sig, signature, source, added_at FROM %s", name))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keySize := 4
+	if name == "events" {
+		keySize = 32
+	}
+	t := abidb.NewTable(keySize)
+	for rows.Next() {
+		var (
+			key, sigs string
+			source    sql.NullString
+			added     sql.NullInt64
+		)
+		if err := rows.Scan(&key, &sigs, &source, &added); err != nil {
+			return nil, err
+		}
+		bin, err := abidb.ParseKey(key)
+		if err == nil && len(bin) != keySize {
+			err = fmt.Errorf("wrong length %d", len(bin))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %v", key, err)
+		}
+		split := strings.Split(sigs, ";")
+		t.Set(fmt.Sprintf("%x", bin), &abidb.Entry{
+			Signature:  split[0],
+			Collisions: split[1:],
+			Source:     source.String,
+			AddedAt:    added.Int64,
+		})
+	}
+	return t, rows.Err()
+}
+
+// isSQLite reports whether the file content starts like a sqlite database.
+func isSQLite(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sqliteMagic))
+}
@@ -0,0 +1,134 @@
+//go:build cgo
+// +build cgo
+
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// sqliteRoundTrip writes the table as sqlite database into dir, and reads it
+// back.
+func sqliteRoundTrip(t *testing.T, dir string, tab *abidb.Table) *abidb.Table {
+	t.Helper()
+	var buf bytes.Buffer
+	n, err := writeSQLite(tab, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) || !isSQLite(buf.Bytes()) {
+		t.Fatalf("invalid database written, %d of %d bytes", n, buf.Len())
+	}
+	file := filepath.Join(dir, "db.sqlite")
+	if err := ioutil.WriteFile(file, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	read, err := readSQLite(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return read
+}
+
+// checkEntry compares the stored parts of an entry read from sqlite.
+func checkEntry(t *testing.T, tab *abidb.Table, key string, want abidb.Entry) {
+	t.Helper()
+	have, ok := tab.Get(key)
+	if !ok {
+		t.Errorf("%s: missing", key)
+		return
+	}
+	if !reflect.DeepEqual(have.Signatures(), want.Signatures()) || have.Source != want.Source || have.AddedAt != want.AddedAt {
+		t.Errorf("%s: have %+v, want %+v", key, have, want)
+	}
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	methods := abidb.NewTable(4)
+	methods.Set("a9059cbb", &abidb.Entry{Signature: "transfer(address,uint256)", Collisions: []string{"many_msg_babbage(bytes1)"}, Source: "a9059cbb", AddedAt: 1600000000})
+	methods.Set("70a08231", &abidb.Entry{Signature: "balanceOf(address)", Source: "list.txt:3", AddedAt: 1700000000})
+	methods.Set("06fdde03", &abidb.Entry{Signature: "name()"})
+
+	start := time.Now().Unix()
+	read := sqliteRoundTrip(t, dir, methods)
+	if read.KeySize() != 4 || read.Len() != methods.Len() {
+		t.Fatalf("have %d entries of %d bytes, want %d of 4", read.Len(), read.KeySize(), methods.Len())
+	}
+	for _, key := range []string{"a9059cbb", "70a08231"} {
+		want, _ := methods.Get(key)
+		checkEntry(t, read, key, *want)
+	}
+	// Entries without build time get the current one
+	if e, _ := read.Get("06fdde03"); e.Signature != "name()" || e.AddedAt < start || e.AddedAt > time.Now().Unix() {
+		t.Errorf("06fdde03: have %+v, want build time stamped", e)
+	}
+
+	// Updating keeps the build times of unchanged entries, and the entries
+	// missing from the input as they were
+	fresh := abidb.NewTable(4)
+	fresh.Set("70a08231", &abidb.Entry{Signature: "balanceOf(address)", Source: "70a08231"})
+	fresh.Set("18160ddd", &abidb.Entry{Signature: "totalSupply()", Source: "18160ddd"})
+	mergeUpdate(read, fresh, 0)
+	updated := sqliteRoundTrip(t, dir, fresh)
+	if updated.Len() != 4 {
+		t.Fatalf("have %d entries, want 4", updated.Len())
+	}
+	checkEntry(t, updated, "a9059cbb", abidb.Entry{Signature: "transfer(address,uint256)", Collisions: []string{"many_msg_babbage(bytes1)"}, Source: "a9059cbb", AddedAt: 1600000000})
+	checkEntry(t, updated, "70a08231", abidb.Entry{Signature: "balanceOf(address)", Source: "70a08231", AddedAt: 1700000000})
+	if e, _ := updated.Get("18160ddd"); e.AddedAt < start {
+		t.Errorf("18160ddd: have %+v, want build time stamped", e)
+	}
+}
+
+func TestSQLiteEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const topic = "ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	events := abidb.NewTable(32)
+	events.Set(topic, &abidb.Entry{Signature: "Transfer(address indexed,address indexed,uint256)", Source: "events.txt:1", AddedAt: 1600000000})
+	read := sqliteRoundTrip(t, dir, events)
+	if read.KeySize() != 32 || read.Len() != 1 {
+		t.Fatalf("have %d entries of %d bytes, want 1 of 32", read.Len(), read.KeySize())
+	}
+	want, _ := events.Get(topic)
+	checkEntry(t, read, topic, *want)
+
+	// An empty events table is still one
+	if read := sqliteRoundTrip(t, dir, abidb.NewTable(32)); read.KeySize() != 32 || read.Len() != 0 {
+		t.Errorf("empty: have %d entries of %d bytes, want none of 32", read.Len(), read.KeySize())
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// loadUpdate reads the existing database to update. Only the methods are
+// updated, so tables of event topics are rejected, instead of merging their
+// keys into the selectors.
+func loadUpdate(file string) (*abidb.Table, error) {
+	existing, err := loadDatabase(file)
+	if err != nil {
+		return nil, err
+	}
+	if existing.KeySize() != 4 {
+		return nil, fmt.Errorf("%v holds %d-byte keys, not method selectors", file, existing.KeySize())
+	}
+	return existing, nil
+}
+
+// mergeUpdate merges the freshly built methods into an existing database, and
+// logs the differences, along with the number of rejected entries as counted
+// by the build report. Selectors present in the input take precedence, the
+// ones only present in the existing database are kept as-is.
+func mergeUpdate(existing *abidb.Table, db *abidb.Table, rejected int) {
+	var added, changed, unchanged []string
+	for _, key := range db.Keys() {
+		e, _ := db.Get(key)
+		old, ok := existing.Get(key)
+		switch {
+		case !ok:
+			added = append(added, key)
+			log.Debug("Added signature", "sig", key, "signature", e.Signature)
+		case old.Signature != e.Signature:
+			changed = append(changed, key)
+			log.Info("Changed signature", "sig", key, "old", old.Signature, "new", e.Signature)
+		default:
+			unchanged = append(unchanged, key)
+			e.AddedAt = old.AddedAt
+		}
+	}
+	kept := 0
+	for _, key := range existing.Keys() {
+		if _, ok := db.Get(key); !ok {
+			e, _ := existing.Get(key)
+			db.Set(key, e)
+			kept++
+		}
+	}
+	log.Info("Updated database", "added", len(added), "changed", len(changed), "unchanged", len(unchanged),
+		"invalid", rejected, "kept", kept)
+}
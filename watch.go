@@ -0,0 +1,111 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/fsnotify/fsnotify"
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+// watchDelay is how long to wait for further changes, before rebuilding. Editors
+// and git usually touch a file several times in a row.
+const watchDelay = 200 * time.Millisecond
+
+// isDir reports whether the inputs are a single signature directory.
+func isDir(inputs []input) bool {
+	if len(inputs) != 1 || (inputs[0].format != "auto" && inputs[0].format != "4bytes") {
+		return false
+	}
+	info, err := os.Stat(inputs[0].path)
+	return err == nil && info.IsDir()
+}
+
+// watchDir watches the signature directory for new or changed files, and
+// validates and adds them to the builder, calling rebuilt after each batch of
+// changes with the number of entries of the batch rejected. Changed files
+// replace the entry of their selector, if valid. It only returns on failure.
+func watchDir(dir string, b *abidb.Builder, rebuilt func(rejected int) error) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if err := w.Add(dir); err != nil {
+		return err
+	}
+	log.Info("Watching input for changes", "dir", dir)
+
+	var (
+		changed = make(map[string]bool)
+		timer   <-chan time.Time // fires once changes settled, nil if none pending
+	)
+	for {
+		select {
+		case ev, ok := <-w.Events:
+			if !ok {
+				return errors.New("watcher closed")
+			}
+			if ev.Op&(fsnotify.Create|fsnotify.Write) == 0 {
+				continue
+			}
+			if _, err := abidb.ParseKey(filepath.Base(ev.Name)); err != nil {
+				continue
+			}
+			changed[filepath.Base(ev.Name)] = true
+			timer = time.After(watchDelay)
+
+		case err, ok := <-w.Errors:
+			if !ok {
+				return errors.New("watcher closed")
+			}
+			return err
+
+		case <-timer:
+			names := make([]string, 0, len(changed))
+			for name := range changed {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			changed, timer = make(map[string]bool), nil
+
+			log.Info("Rebuilding on changed signature files", "files", len(names))
+			report.start = time.Now()
+			rejected := report.rejected()
+			err := processRecords(b, int64(len(names)), func(emit func(*record)) error {
+				for _, name := range names {
+					r := fileRecord(dir, name, b)
+					r.keepFirst = false
+					emit(r)
+				}
+				return nil
+			}, func(r *record) { r.commit(b) })
+			if err != nil {
+				return err
+			}
+			if err := rebuilt(report.rejected() - rejected); err != nil {
+				return err
+			}
+		}
+	}
+}
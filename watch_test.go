@@ -0,0 +1,83 @@
+// Copyright 2026 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/holiman/abidbbuilder/abidb"
+)
+
+func TestWatchRebuild(t *testing.T) {
+	defer func(r *buildReport) { report = r }(report)
+	report = &buildReport{Rejected: make(map[string]int), start: time.Now()}
+
+	dir, err := ioutil.TempDir("", "abidb-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		b       = abidb.NewBuilder()
+		rebuilt = make(chan int)
+		errDone = errors.New("done")
+		done    = make(chan error)
+	)
+	go func() {
+		done <- watchDir(dir, b, func(rejected int) error {
+			rebuilt <- rejected
+			if rejected > 0 {
+				return errDone
+			}
+			return nil
+		})
+	}()
+	time.Sleep(100 * time.Millisecond) // let the watcher start
+
+	batches := []struct {
+		name, sig string
+		rejected  int
+	}{
+		{"095ea7b3", "approve(address,uint256)", 0},
+		{"70a08231", "balanceOf()", 1},
+	}
+	for _, batch := range batches {
+		writeFiles(t, dir, map[string]string{batch.name: batch.sig})
+		select {
+		case rejected := <-rebuilt:
+			if rejected != batch.rejected {
+				t.Errorf("%v: rejected %d, want %d", batch.name, rejected, batch.rejected)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%v: no rebuild", batch.name)
+		}
+	}
+	if err := <-done; err != errDone {
+		t.Fatalf("watch failed: %v", err)
+	}
+	if _, ok := b.Database().Methods.Get("095ea7b3"); !ok {
+		t.Error("added signature missing")
+	}
+	if n := len(b.Mismatches()); n != 1 {
+		t.Errorf("%d mismatches, want 1", n)
+	}
+}